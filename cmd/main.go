@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/LFroesch/logdog/internal/cli"
 	"github.com/LFroesch/logdog/internal/logdog"
 	"github.com/LFroesch/logdog/internal/tui"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(cli.Run(os.Args[1:]))
+	}
+
 	logdog.Info("Starting Logdog...")
 	p := tea.NewProgram(tui.NewModel(), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
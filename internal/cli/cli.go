@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is a non-interactive subcommand run as `logdog <name> [flags]`.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{name: "install", summary: "install the logger into the current project", run: runInstall},
+	{name: "query", summary: "find log entries across a project's log files", run: runQuery},
+	{name: "numbers", summary: "percentiles and a histogram of a numeric data field", run: runNumbers},
+}
+
+// Run executes the subcommand named by args[0] and returns the process exit
+// code.
+func Run(args []string) int {
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return 2
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			if err := cmd.run(args[1:], os.Stdout, os.Stderr); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			return 0
+		}
+	}
+
+	switch args[0] {
+	case "help", "-h", "--help":
+		usage(os.Stdout)
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+	usage(os.Stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: logdog [command] [flags]")
+	fmt.Fprintln(w, "\nRun without a command to open the TUI.")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/LFroesch/logdog/internal/detector"
+)
+
+func runInstall(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("install", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dryRun := fs.Bool("dry-run", false, "print the files and directories that would be written, without touching disk")
+	pkgPath := fs.String("dir", "", "directory for the generated package, relative to the project root (default from .logdog.json, else internal/logdog)")
+	pkgName := fs.String("package", "", "name of the generated package (default from .logdog.json, else logdog)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: logdog install [--dry-run] [--dir path] [--package name] [project-path]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	projectPath, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		if projectPath, err = filepath.Abs(fs.Arg(0)); err != nil {
+			return err
+		}
+	}
+
+	lang := detector.DetectLanguage(projectPath)
+	if lang == nil {
+		return fmt.Errorf("no supported project detected in %s", projectPath)
+	}
+
+	config, err := detector.LoadProjectConfig(projectPath)
+	if err != nil {
+		return err
+	}
+	if *pkgPath != "" {
+		config.PackagePath = *pkgPath
+	}
+	if *pkgName != "" {
+		config.PackageName = *pkgName
+	}
+
+	plan, err := lang.PlanInstall(projectPath, config)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Fprint(stdout, plan.String())
+		return nil
+	}
+
+	fmt.Fprint(stdout, plan.Summary())
+	if err := plan.Apply(); err != nil {
+		return err
+	}
+	// Remembering the project's source for the TUI is best effort.
+	if state, err := detector.LoadState(); err == nil && state.RememberSource(plan.LogDir, projectPath) {
+		detector.SaveState(state)
+	}
+	fmt.Fprintf(stdout, "\nImport it with:\n\n\timport %q\n", plan.ImportPath)
+	return nil
+}
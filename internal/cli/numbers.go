@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+	"github.com/LFroesch/logdog/internal/query"
+	"github.com/LFroesch/logdog/internal/stats"
+)
+
+// histogramWidth is the length of the longest histogram bar.
+const histogramWidth = 40
+
+func runNumbers(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("numbers", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	project := fs.String("project", "", "read the logs of this project under the log root instead of the current project's")
+	dir := fs.String("dir", "", "read the log files in this directory")
+	file := fs.String("file", "", "read a single log file")
+	by := fs.String("by", "", "also break the numbers down by the values of this data key")
+	top := fs.Int("top", 20, "how many groups to list with --by (0 for all)")
+	buckets := fs.Int("buckets", 10, "number of histogram buckets (0 for none)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: logdog numbers [--project name | --dir path | --file path] [--by key] [--top n] [--buckets n] field ['query']")
+		fmt.Fprintln(stderr, "\nExample: logdog numbers --by path duration_ms 'level>=INFO since 1d'")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("missing field")
+	}
+
+	q, err := query.Parse(strings.Join(fs.Args()[1:], " "), time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	files, err := queryFiles(q, *project, *dir, *file)
+	if err != nil {
+		return err
+	}
+
+	numbers := stats.NewNumbers(fs.Arg(0), *by)
+	err = q.Run(files, func(_ string, entry logs.Entry) error {
+		numbers.Add(entry)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if numbers.All.Count() == 0 {
+		return fmt.Errorf("no numeric values of %s found in %d files", numbers.Field, len(files))
+	}
+
+	fmt.Fprintf(stdout, "%s  (%d values)\n", numbers.Field, numbers.All.Count())
+	fmt.Fprintln(stdout, "  "+summarize(numbers.All))
+
+	if *buckets > 0 {
+		fmt.Fprintln(stdout)
+		histogram := numbers.All.Histogram(*buckets)
+		peak := 0
+		for _, b := range histogram {
+			peak = max(peak, b.Count)
+		}
+		labels := make([]string, len(histogram))
+		labelWidth := 0
+		for i, b := range histogram {
+			labels[i] = stats.FormatNumber(b.Low) + " – " + stats.FormatNumber(b.High)
+			labelWidth = max(labelWidth, len([]rune(labels[i])))
+		}
+		for i, b := range histogram {
+			bar := strings.Repeat("█", b.Count*histogramWidth/peak)
+			if b.Count > 0 && bar == "" {
+				bar = "▏"
+			}
+			fmt.Fprintf(stdout, "  %s%s  %s %d\n", labels[i], strings.Repeat(" ", labelWidth-len([]rune(labels[i]))), bar, b.Count)
+		}
+	}
+
+	if numbers.By == "" {
+		return nil
+	}
+
+	groups := numbers.ByCount()
+	if *top > 0 && len(groups) > *top {
+		groups = groups[:*top]
+	}
+	nameWidth := len(numbers.By)
+	for _, g := range groups {
+		nameWidth = max(nameWidth, len([]rune(g.Name)))
+	}
+	fmt.Fprintf(stdout, "\nby %s (%d groups)\n", numbers.By, len(numbers.Groups))
+	fmt.Fprintf(stdout, "  %-*s %8s %10s %10s %10s %10s %10s %10s\n", nameWidth, strings.ToUpper(numbers.By),
+		"COUNT", "MIN", "MEAN", "P50", "P95", "P99", "MAX")
+	for _, g := range groups {
+		fmt.Fprintf(stdout, "  %s%s %8d %10s %10s %10s %10s %10s %10s\n", g.Name, strings.Repeat(" ", nameWidth-len([]rune(g.Name))),
+			g.Count(), stats.FormatNumber(g.Min()), stats.FormatNumber(g.Mean()), stats.FormatNumber(g.Percentile(50)),
+			stats.FormatNumber(g.Percentile(95)), stats.FormatNumber(g.Percentile(99)), stats.FormatNumber(g.Max()))
+	}
+	return nil
+}
+
+// summarize renders the headline numbers of a distribution on one line.
+func summarize(d *stats.Distribution) string {
+	return fmt.Sprintf("min %s  mean %s  p50 %s  p95 %s  p99 %s  max %s",
+		stats.FormatNumber(d.Min()), stats.FormatNumber(d.Mean()), stats.FormatNumber(d.Percentile(50)),
+		stats.FormatNumber(d.Percentile(95)), stats.FormatNumber(d.Percentile(99)), stats.FormatNumber(d.Max()))
+}
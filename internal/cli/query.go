@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/detector"
+	"github.com/LFroesch/logdog/internal/logs"
+	"github.com/LFroesch/logdog/internal/query"
+)
+
+func runQuery(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	project := fs.String("project", "", "query the logs of this project under the log root instead of the current project's")
+	dir := fs.String("dir", "", "query the log files in this directory")
+	file := fs.String("file", "", "query a single log file")
+	asJSON := fs.Bool("json", false, "print matching lines as they appear in the log")
+	count := fs.Bool("count", false, "only print the number of matching entries")
+	limit := fs.Int("limit", 0, "stop after this many matches (0 for no limit)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: logdog query [--project name | --dir path | --file path] [--json] [--count] [--limit n] 'query'")
+		fmt.Fprintln(stderr, "\nExample: logdog query 'level>=WARN and data.user_id=123 and message~\"timeout\" since 2h'")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	q, err := query.Parse(strings.Join(fs.Args(), " "), time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	files, err := queryFiles(q, *project, *dir, *file)
+	if err != nil {
+		return err
+	}
+
+	matches := 0
+	err = q.Run(files, func(_ string, entry logs.Entry) error {
+		matches++
+		switch {
+		case *count:
+		case *asJSON:
+			fmt.Fprintln(stdout, entry.Raw)
+		default:
+			fmt.Fprintln(stdout, formatEntry(entry))
+		}
+		if *limit > 0 && matches >= *limit {
+			return query.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if *count {
+		fmt.Fprintln(stdout, matches)
+	}
+	return nil
+}
+
+// queryFiles picks the files a query runs over: a single file, a directory,
+// a project under the log root, or else the current project's log directory.
+func queryFiles(q *query.Query, project, dir, file string) ([]string, error) {
+	if file != "" {
+		return []string{file}, nil
+	}
+
+	switch {
+	case dir != "":
+	case project != "":
+		root, err := detector.LogRoot()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(root, project)
+	default:
+		projectPath, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		config, err := detector.LoadProjectConfig(projectPath)
+		if err != nil {
+			return nil, err
+		}
+		if dir, err = detector.ResolveLogDir(projectPath, config); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("no logs found: %w", err)
+	}
+	return q.Files(dir)
+}
+
+// formatEntry renders an entry as a single plain-text line.
+func formatEntry(entry logs.Entry) string {
+	if !entry.Valid {
+		return entry.Raw
+	}
+
+	var b strings.Builder
+	b.WriteString(entry.Timestamp)
+	b.WriteString(" [" + entry.Level + "] ")
+	b.WriteString(entry.Message)
+
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(" " + k + "=" + logs.FormatValue(entry.Data[k]))
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectConfigFile is the per-project config, committed at the project
+// root so everyone working on the project gets the same behavior.
+const ProjectConfigFile = ".logdog.json"
+
+// LoadProjectConfig reads the project's .logdog.json on top of the global
+// defaults. A missing file is not an error. An output directory that is
+// unsafe to write logs to is dropped, with an error saying why.
+func LoadProjectConfig(projectPath string) (Config, error) {
+	defaults := globalConfig().Defaults
+	config := defaults
+
+	data, err := os.ReadFile(filepath.Join(projectPath, ProjectConfigFile))
+	if os.IsNotExist(err) {
+		return checkConfigOutputDir(projectPath, config)
+	}
+	if err != nil {
+		return config, fmt.Errorf("failed to read %s: %w", ProjectConfigFile, err)
+	}
+
+	// Levels the file leaves out of its retention policy keep the defaults.
+	config.Retention = RetentionPolicy{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return defaults, fmt.Errorf("invalid %s: %w", ProjectConfigFile, err)
+	}
+	config.fillRetention(defaults.Retention)
+	return checkConfigOutputDir(projectPath, config)
+}
+
+func checkConfigOutputDir(projectPath string, config Config) (Config, error) {
+	if err := CheckOutputDir(projectPath, config.OutputDir); err != nil {
+		config.OutputDir = ""
+		return config, err
+	}
+	return config, nil
+}
+
+// SaveProjectConfig writes config to the project's .logdog.json.
+func SaveProjectConfig(projectPath string, config Config) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(projectPath, ProjectConfigFile)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ProjectConfigFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// GlobalConfig is the user's own logdog configuration, stored in
+// $XDG_CONFIG_HOME/logdog/config.json. Defaults apply to every project that
+// doesn't override them in its .logdog.json.
+type GlobalConfig struct {
+	Defaults Config `json:"defaults"`
+	// ViewerLevel is the lowest level shown when opening a log file.
+	ViewerLevel string `json:"default_log_level"`
+	Theme       string `json:"theme"`
+	// LogRoot replaces ~/logdog as the directory holding every project's logs.
+	LogRoot string `json:"log_root"`
+	// CorrelationKeys are the data keys that tie entries of one request or
+	// trace together, tried in order.
+	CorrelationKeys []string `json:"correlation_keys"`
+	// Projects holds viewer preferences per project, keyed by the name of
+	// the project's log directory.
+	Projects map[string]ProjectSettings `json:"projects,omitempty"`
+}
+
+// ProjectSettings are the log viewer preferences remembered for a project.
+type ProjectSettings struct {
+	// TableView opens the project's logs as a table.
+	TableView bool `json:"table_view,omitempty"`
+	// TableColumns are the data keys shown as table columns, each optionally
+	// followed by ":width".
+	TableColumns []string `json:"table_columns,omitempty"`
+}
+
+var (
+	globalMu     sync.Mutex
+	globalCached *GlobalConfig
+)
+
+// DefaultGlobalConfig is the global configuration used when there is no
+// config file.
+func DefaultGlobalConfig() GlobalConfig {
+	return GlobalConfig{
+		Defaults:        DefaultConfig(),
+		ViewerLevel:     "DEBUG",
+		Theme:           "default",
+		CorrelationKeys: []string{"request_id", "trace_id", "correlation_id"},
+	}
+}
+
+// GlobalConfigPath returns the location of the global config file.
+func GlobalConfigPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// LoadGlobalConfig reads the global config file. A missing file is not an
+// error.
+func LoadGlobalConfig() (GlobalConfig, error) {
+	config := DefaultGlobalConfig()
+
+	path, err := GlobalConfigPath()
+	if err != nil {
+		return config, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	config.Defaults.Retention = RetentionPolicy{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return DefaultGlobalConfig(), fmt.Errorf("invalid %s: %w", path, err)
+	}
+	config.Defaults.fillRetention(DefaultRetention())
+	if len(config.CorrelationKeys) == 0 {
+		config.CorrelationKeys = DefaultGlobalConfig().CorrelationKeys
+	}
+	return config, nil
+}
+
+// SaveGlobalConfig writes the global config file, creating its directory if
+// needed.
+func SaveGlobalConfig(config GlobalConfig) error {
+	path, err := GlobalConfigPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	globalMu.Lock()
+	globalCached = &config
+	globalMu.Unlock()
+	return nil
+}
+
+// globalConfig returns the global config, loading it on first use. Errors
+// fall back to the defaults; LoadGlobalConfig reports them to the user.
+func globalConfig() GlobalConfig {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	if globalCached == nil {
+		config, _ := LoadGlobalConfig()
+		globalCached = &config
+	}
+	return *globalCached
+}
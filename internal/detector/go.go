@@ -2,10 +2,12 @@ package detector
 
 import (
 	"fmt"
+	"go/parser"
+	"go/token"
 	"os"
-	"os/user"
+	"path"
 	"path/filepath"
-	"text/template"
+	"strings"
 )
 
 type GoLanguage struct{}
@@ -21,79 +23,169 @@ func (g *GoLanguage) Detect(projectPath string) bool {
 }
 
 func (g *GoLanguage) Install(projectPath string, config Config) error {
-	fmt.Printf("DEBUG: Installing in %s\n", projectPath)
-
-	// Get user home directory
-	usr, err := user.Current()
+	plan, err := g.PlanInstall(projectPath, config)
 	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
+		return err
 	}
+	return plan.Apply()
+}
 
-	// Get project name from path
-	projectName := filepath.Base(projectPath)
-	
-	// Create ~/logdog/<project-name> directory structure
-	homeLogdogDir := filepath.Join(usr.HomeDir, "logdog")
-	projectLogDir := filepath.Join(homeLogdogDir, projectName)
+// PlanInstall renders the logger package and works out which directories
+// and files Install would create or overwrite, without touching disk.
+func (g *GoLanguage) PlanInstall(projectPath string, config Config) (*InstallPlan, error) {
+	config = withPackageDefaults(config)
+	if err := validatePackage(config); err != nil {
+		return nil, err
+	}
+
+	module, err := modulePath(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	importPath, err := g.ImportPath(projectPath, config)
+	if err != nil {
+		return nil, err
+	}
 
-	fmt.Printf("DEBUG: Creating %s\n", projectLogDir)
-	if err := os.MkdirAll(projectLogDir, 0755); err != nil {
-		return fmt.Errorf("failed to create logs directory: %w", err)
+	// Logs go to ~/logdog/<project-name> unless configured otherwise
+	projectLogDir, err := ResolveLogDir(projectPath, config)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create internal/logdog directory
-	internalDir := filepath.Join(projectPath, "internal", "logdog")
-	fmt.Printf("DEBUG: Creating %s\n", internalDir)
-	if err := os.MkdirAll(internalDir, 0755); err != nil {
-		return fmt.Errorf("failed to create internal directory: %w", err)
+	pkgDir := filepath.Join(projectPath, filepath.FromSlash(config.PackagePath))
+	if existing := existingPackageName(pkgDir); existing != "" && existing != config.PackageName {
+		return nil, fmt.Errorf("%s already contains package %s; choose another location", config.PackagePath, existing)
+	}
+	loggerPath := filepath.Join(pkgDir, "logger.go")
+	if _, err := os.Stat(loggerPath); err == nil && !isGeneratedLogger(loggerPath) {
+		return nil, fmt.Errorf("%s/logger.go was not generated by logdog; choose another location", config.PackagePath)
 	}
 
-	// Generate logger.go with new log directory
-	loggerPath := filepath.Join(internalDir, "logger.go")
-	fmt.Printf("DEBUG: Generating %s\n", loggerPath)
-	
 	// Update config to use the new log directory
 	updatedConfig := config
 	updatedConfig.OutputDir = projectLogDir
-	
-	if err := g.generateLogger(loggerPath, updatedConfig); err != nil {
-		return fmt.Errorf("failed to generate logger: %w", err)
+
+	data := TemplateData{
+		Config:     updatedConfig,
+		ImportPath: importPath,
+		Project: ProjectInfo{
+			Name:     filepath.Base(projectPath),
+			Path:     projectPath,
+			Module:   module,
+			Language: g.Name(),
+			LogDir:   projectLogDir,
+			LogName:  filepath.Base(projectLogDir),
+		},
+	}
+
+	logger, loggerSource, err := g.generateLogger(projectPath, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate logger: %w", err)
+	}
+	// Uninstall finds the package by this header, so keep it even when a
+	// custom template leaves it out.
+	if !strings.HasPrefix(logger, generatedMarker) {
+		logger = generatedHeader + "\n" + logger
+	}
+
+	readme, readmeSource, err := g.generateReadme(projectPath, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate README: %w", err)
+	}
+
+	return &InstallPlan{
+		Language:    g.Name(),
+		ProjectPath: projectPath,
+		LogDir:      projectLogDir,
+		ImportPath:  importPath,
+		Dirs: []PlannedDir{
+			planDir(projectLogDir),
+			planDir(pkgDir),
+		},
+		Files: []PlannedFile{
+			planFile(loggerPath, logger, loggerSource),
+			planFile(filepath.Join(pkgDir, "README.md"), readme, readmeSource),
+		},
+	}, nil
+}
+
+// ImportPath is the import path of the generated package, derived from the
+// module line in go.mod.
+func (g *GoLanguage) ImportPath(projectPath string, config Config) (string, error) {
+	config = withPackageDefaults(config)
+	module, err := modulePath(projectPath)
+	if err != nil {
+		return "", err
 	}
+	return path.Join(module, filepath.ToSlash(config.PackagePath)), nil
+}
 
-	// Generate README.md
-	readmePath := filepath.Join(internalDir, "README.md")
-	fmt.Printf("DEBUG: Generating %s\n", readmePath)
-	if err := g.generateReadme(readmePath); err != nil {
-		return fmt.Errorf("failed to generate README: %w", err)
+func withPackageDefaults(config Config) Config {
+	defaults := DefaultConfig()
+	if config.PackagePath == "" {
+		config.PackagePath = defaults.PackagePath
 	}
+	if config.PackageName == "" {
+		config.PackageName = defaults.PackageName
+	}
+	config.PackagePath = path.Clean(filepath.ToSlash(config.PackagePath))
+	return config
+}
 
-	fmt.Printf("DEBUG: Installation complete!\n")
+func validatePackage(config Config) error {
+	if !token.IsIdentifier(config.PackageName) || token.IsKeyword(config.PackageName) {
+		return fmt.Errorf("%q is not a valid Go package name", config.PackageName)
+	}
+	if path.IsAbs(config.PackagePath) || config.PackagePath == "." ||
+		config.PackagePath == ".." || strings.HasPrefix(config.PackagePath, "../") {
+		return fmt.Errorf("package path %q must be inside the project", config.PackagePath)
+	}
 	return nil
 }
 
-func (g *GoLanguage) generateReadme(outputPath string) error {
-	file, err := os.Create(outputPath)
+// existingPackageName returns the package clause of the first non-logdog Go
+// file in dir, or "" if there is none.
+func existingPackageName(dir string) string {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return err
+		return ""
 	}
-	defer file.Close()
 
-	_, err = file.WriteString(readmeContent)
-	return err
+	for _, entry := range entries {
+		goFile := filepath.Join(dir, entry.Name())
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".go" || strings.HasSuffix(entry.Name(), "_test.go") || isGeneratedLogger(goFile) {
+			continue
+		}
+		if name := packageClause(goFile); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// installedPackageName returns the package clause of the logger installed
+// in dir, or "" if there is none.
+func installedPackageName(dir string) string {
+	return packageClause(filepath.Join(dir, "logger.go"))
+}
+
+// packageClause returns the package name a Go file declares, or "" if it
+// can't be parsed.
+func packageClause(goFile string) string {
+	file, err := parser.ParseFile(token.NewFileSet(), goFile, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return ""
+	}
+	return file.Name.Name
 }
 
 func (g *GoLanguage) GetLogPaths(projectPath string) []string {
-	// Get user home directory
-	usr, err := user.Current()
+	config, _ := LoadProjectConfig(projectPath)
+	logsDir, err := ResolveLogDir(projectPath, config)
 	if err != nil {
 		return []string{}
 	}
-
-	// Get project name from path
-	projectName := filepath.Base(projectPath)
-	
-	// Look in ~/logdog/<project-name>/
-	logsDir := filepath.Join(usr.HomeDir, "logdog", projectName)
 	var paths []string
 
 	filepath.Walk(logsDir, func(path string, info os.FileInfo, err error) error {
@@ -109,22 +201,16 @@ func (g *GoLanguage) GetLogPaths(projectPath string) []string {
 	return paths
 }
 
-func (g *GoLanguage) generateLogger(outputPath string, config Config) error {
-	tmpl := template.Must(template.New("logger").Parse(goLoggerTemplate))
-
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	data := struct {
-		Config Config
-	}{
-		Config: config,
-	}
+// generateLogger renders logger.go from the user's logger.go.tmpl override
+// if there is one, or from goLoggerTemplate.
+func (g *GoLanguage) generateLogger(projectPath string, data TemplateData) (string, string, error) {
+	return renderTemplate(projectPath, g.Name(), "logger.go.tmpl", goLoggerTemplate, data)
+}
 
-	return tmpl.Execute(file, data)
+// generateReadme renders README.md from the user's README.md.tmpl override
+// if there is one, or from readmeContent.
+func (g *GoLanguage) generateReadme(projectPath string, data TemplateData) (string, string, error) {
+	return renderTemplate(projectPath, g.Name(), "README.md.tmpl", readmeContent, data)
 }
 
 const readmeContent = `# 🐕 Logdog
@@ -144,17 +230,17 @@ Logdog is a terminal-based app that:
 This logger was generated by Logdog. To use it in your code:
 
 ` + "```go" + `
-import "your-project/internal/logdog"
+import "{{.ImportPath}}"
 
 func main() {
    // Simple message
-   logdog.Info("Application started")
+   {{.Config.PackageName}}.Info("Application started")
    
    // With additional data
-   logdog.Info("User logged in", "user_id", 123, "username", "john")
+   {{.Config.PackageName}}.Info("User logged in", "user_id", 123, "username", "john")
    
    // Error with context
-   logdog.Error("Database error", "table", "users", "operation", "insert")
+   {{.Config.PackageName}}.Error("Database error", "table", "users", "operation", "insert")
 }
 ` + "```" + `
 
@@ -162,16 +248,16 @@ func main() {
 
 ### Basic Logging
 ` + "```go" + `
-logdog.Debug("Debug message")
-logdog.Info("Info message") 
-logdog.Warn("Warning message")
-logdog.Error("Error message")
+{{.Config.PackageName}}.Debug("Debug message")
+{{.Config.PackageName}}.Info("Info message") 
+{{.Config.PackageName}}.Warn("Warning message")
+{{.Config.PackageName}}.Error("Error message")
 ` + "```" + `
 
 ### With Additional Data
 ` + "```go" + `
 // Pass key-value pairs as arguments
-logdog.Info("User action", 
+{{.Config.PackageName}}.Info("User action", 
    "user_id", 123,
    "action", "login",
    "ip", "192.168.1.1")
@@ -179,7 +265,7 @@ logdog.Info("User action",
 
 ## Log Output
 
-Logs are written as JSON to ` + "`~/logdog/<project-name>/<project-name>-logdog-MM-DD-YYYY.json`" + `:
+Logs are written as JSON to ` + "`{{.Project.LogDir}}/{{.Project.LogName}}-logdog-MM-DD-YYYY.json`" + `:
 
 ` + "```json" + `
 {
@@ -199,14 +285,13 @@ Logs are written as JSON to ` + "`~/logdog/<project-name>/<project-name>-logdog-
 Your project now has:
 ` + "```" + `
 your-project/
-├── internal/logdog/
+├── {{.Config.PackagePath}}/
 │   ├── logger.go          # Generated logging package
 │   └── README.md          # This documentation
 └── go.mod
 
-~/logdog/
-└── your-project/
-    └── your-project-logdog-01-15-2024.json
+{{.Project.LogDir}}/
+└── {{.Project.LogName}}-logdog-01-15-2024.json
 ` + "```" + `
 
 ## Best Practices
@@ -225,7 +310,7 @@ your-project/
 ### Web Server Logging
 ` + "```go" + `
 // Request logging
-logdog.Info("HTTP request", 
+{{.Config.PackageName}}.Info("HTTP request", 
    "method", r.Method,
    "path", r.URL.Path,
    "user_id", userID,
@@ -233,7 +318,7 @@ logdog.Info("HTTP request",
 
 // Error handling
 if err != nil {
-   logdog.Error("Database query failed", 
+   {{.Config.PackageName}}.Error("Database query failed", 
        "user_id", userID,
        "error", err.Error(),
        "query", "SELECT * FROM users")
@@ -241,7 +326,7 @@ if err != nil {
 }
 
 // Business events
-logdog.Info("Order created",
+{{.Config.PackageName}}.Info("Order created",
    "user_id", userID,
    "order_id", order.ID,
    "total", order.Total)
@@ -249,27 +334,30 @@ logdog.Info("Order created",
 
 ### Background Jobs
 ` + "```go" + `
-logdog.Info("Job started", "job_type", "email_sender", "batch_size", 100)
+{{.Config.PackageName}}.Info("Job started", "job_type", "email_sender", "batch_size", 100)
 
 for _, email := range emails {
    if err := sendEmail(email); err != nil {
-       logdog.Error("Email send failed", 
+       {{.Config.PackageName}}.Error("Email send failed", 
            "recipient", email.To,
            "template", email.Template,
            "error", err.Error())
        continue
    }
-   logdog.Debug("Email sent", "recipient", email.To)
+   {{.Config.PackageName}}.Debug("Email sent", "recipient", email.To)
 }
 
-logdog.Info("Job completed", "sent", sentCount, "failed", failedCount)
+{{.Config.PackageName}}.Info("Job completed", "sent", sentCount, "failed", failedCount)
 ` + "```" + `
 
 ---
 
 Generated by Logdog - Simple structured logging for Go projects.`
 
-const goLoggerTemplate = `package logdog
+const generatedHeader = generatedMarker + " Re-run the install to regenerate."
+
+const goLoggerTemplate = generatedHeader + `
+package {{.Config.PackageName}}
 
 import (
 	"encoding/json"
@@ -289,6 +377,13 @@ const (
 	ERROR LogLevel = "ERROR"
 )
 
+var levelRank = map[LogLevel]int{
+	DEBUG: 0,
+	INFO:  1,
+	WARN:  2,
+	ERROR: 3,
+}
+
 type LogEntry struct {
 	Timestamp string              ` + "`json:\"timestamp\"`" + `
 	Level     LogLevel               ` + "`json:\"level\"`" + `
@@ -308,13 +403,17 @@ var once sync.Once
 func init() {
 	once.Do(func() {
 		defaultLogger = &Logger{
-			logLevel: INFO,
-			logDir:   "{{.Config.OutputDir}}",
+			logLevel: LogLevel("{{.Config.LogLevel}}"),
+			logDir:   {{printf "%q" .Config.OutputDir}},
 		}
 	})
 }
 
 func (l *Logger) log(level LogLevel, message string, data map[string]interface{}) {
+	if levelRank[level] < levelRank[l.logLevel] {
+		return
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
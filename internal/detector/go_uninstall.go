@@ -0,0 +1,375 @@
+package detector
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// generatedMarker starts the first line of every generated logger.go.
+const generatedMarker = "// Generated by logdog."
+
+// slogFuncs are the generated logger functions that have a drop-in
+// log/slog equivalent with the same (msg string, args ...any) signature.
+var slogFuncs = map[string]bool{
+	"Debug": true,
+	"Info":  true,
+	"Warn":  true,
+	"Error": true,
+}
+
+// installedFiles are the files the install writes into the logger package.
+var installedFiles = []string{"logger.go", "README.md"}
+
+// Uninstall removes the files the install wrote, then the package directory
+// if nothing else is left in it. Files added to the package by hand stay.
+func (g *GoLanguage) Uninstall(projectPath string) error {
+	pkgDir, err := g.findLoggerDir(projectPath)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range installedFiles {
+		if err := os.Remove(filepath.Join(pkgDir, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+	}
+	removeEmptyParents(pkgDir, projectPath)
+	return nil
+}
+
+// removeEmptyParents removes dir and its parents up to, but not including,
+// root for as long as they are empty, e.g. internal/logdog and the
+// internal/ that held only it.
+func removeEmptyParents(dir, root string) {
+	root = filepath.Clean(root)
+	for dir = filepath.Clean(dir); dir != root; dir = filepath.Dir(dir) {
+		if rel, err := filepath.Rel(root, dir); err != nil || strings.HasPrefix(rel, "..") {
+			return
+		}
+		// Remove fails on a directory that isn't empty, which ends the walk.
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
+
+// CallSites lists the project's Go files (relative to projectPath) that
+// import the generated logger package.
+func (g *GoLanguage) CallSites(projectPath string) ([]string, error) {
+	pkgDir, importPath, err := g.installedPackage(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err = g.walkGoFiles(projectPath, pkgDir, func(path string) error {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			return nil
+		}
+		if findImport(file, importPath) != nil {
+			rel, _ := filepath.Rel(projectPath, path)
+			files = append(files, rel)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// RewriteCallSites switches every logdog.Debug/Info/Warn/Error call back to
+// log/slog. Files that use anything else from the generated package are
+// left untouched and reported in remaining.
+func (g *GoLanguage) RewriteCallSites(projectPath string) ([]string, []string, error) {
+	pkgDir, importPath, err := g.installedPackage(projectPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pkgName := installedPackageName(pkgDir)
+
+	var rewritten, remaining []string
+	err = g.walkGoFiles(projectPath, pkgDir, func(path string) error {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return nil
+		}
+		if findImport(file, importPath) == nil {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(projectPath, path)
+		if !rewriteToSlog(file, importPath, pkgName) {
+			remaining = append(remaining, rel)
+			return nil
+		}
+
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, file); err != nil {
+			return fmt.Errorf("failed to format %s: %w", rel, err)
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", rel, err)
+		}
+		rewritten = append(rewritten, rel)
+		return nil
+	})
+
+	sort.Strings(rewritten)
+	sort.Strings(remaining)
+	return rewritten, remaining, err
+}
+
+// walkGoFiles calls fn for every .go file in the project, skipping skipDir
+// (the generated logger itself), vendored code and hidden directories.
+func (g *GoLanguage) walkGoFiles(projectPath, skipDir string, fn func(path string) error) error {
+	return filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path == skipDir || name == "vendor" || name == "testdata" ||
+				(strings.HasPrefix(name, ".") && path != projectPath) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" {
+			return nil
+		}
+		return fn(path)
+	})
+}
+
+// findLoggerDir locates the installed logger package by the marker comment
+// at the top of the generated logger.go. Loggers generated before the marker
+// existed are looked for in the old fixed location, internal/logdog, and
+// recognized by the declarations that template had.
+func (g *GoLanguage) findLoggerDir(projectPath string) (string, error) {
+	var found string
+	g.walkGoFiles(projectPath, "", func(path string) error {
+		if filepath.Base(path) == "logger.go" && isGeneratedLogger(path) {
+			found = filepath.Dir(path)
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if found != "" {
+		return found, nil
+	}
+
+	legacy := filepath.Join(projectPath, "internal", "logdog")
+	if isLegacyLogger(filepath.Join(legacy, "logger.go")) {
+		return legacy, nil
+	}
+	return "", fmt.Errorf("no logdog logger installed in %s", projectPath)
+}
+
+func (g *GoLanguage) installedPackage(projectPath string) (dir, importPath string, err error) {
+	dir, err = g.findLoggerDir(projectPath)
+	if err != nil {
+		return "", "", err
+	}
+	rel, err := filepath.Rel(projectPath, dir)
+	if err != nil {
+		return "", "", err
+	}
+	importPath, err = g.ImportPath(projectPath, Config{PackagePath: rel})
+	return dir, importPath, err
+}
+
+func isGeneratedLogger(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	return scanner.Scan() && strings.HasPrefix(scanner.Text(), generatedMarker)
+}
+
+// isLegacyLogger reports whether path is a logger.go generated before the
+// marker existed: package logdog with the LogLevel type, and a Logger
+// holding logLevel and logDir that defaultLogger points to.
+func isLegacyLogger(path string) bool {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil || file.Name.Name != "logdog" {
+		return false
+	}
+
+	var levelType, loggerFields, defaultLogger bool
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				switch spec.Name.Name {
+				case "LogLevel":
+					ident, ok := spec.Type.(*ast.Ident)
+					levelType = ok && ident.Name == "string"
+				case "Logger":
+					if st, ok := spec.Type.(*ast.StructType); ok {
+						fields := map[string]bool{}
+						for _, field := range st.Fields.List {
+							for _, name := range field.Names {
+								fields[name.Name] = true
+							}
+						}
+						loggerFields = fields["logLevel"] && fields["logDir"]
+					}
+				}
+			case *ast.ValueSpec:
+				for _, name := range spec.Names {
+					defaultLogger = defaultLogger || name.Name == "defaultLogger"
+				}
+			}
+		}
+	}
+	return levelType && loggerFields && defaultLogger
+}
+
+// modulePath reads the module path from the project's go.mod.
+func modulePath(projectPath string) (string, error) {
+	file, err := os.Open(filepath.Join(projectPath, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "module") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "module" {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(fields[1]); err == nil {
+			return unquoted, nil
+		}
+		return fields[1], nil
+	}
+	return "", fmt.Errorf("no module line found in go.mod")
+}
+
+func findImport(file *ast.File, importPath string) *ast.ImportSpec {
+	for _, spec := range file.Imports {
+		if path, err := strconv.Unquote(spec.Path.Value); err == nil && path == importPath {
+			return spec
+		}
+	}
+	return nil
+}
+
+// rewriteToSlog replaces the logger import with log/slog and renames the
+// package qualifier on every call. It returns false without modifying the
+// file if the file references anything slog has no equivalent for.
+func rewriteToSlog(file *ast.File, importPath, pkgName string) bool {
+	spec := findImport(file, importPath)
+	local := pkgName
+	if spec.Name != nil {
+		local = spec.Name.Name
+	}
+	if local == "." {
+		return false
+	}
+
+	var selectors []*ast.SelectorExpr
+	supported := true
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if id, ok := sel.X.(*ast.Ident); ok && id.Name == local && id.Obj == nil {
+			if !slogFuncs[sel.Sel.Name] {
+				supported = false
+			}
+			selectors = append(selectors, sel)
+		}
+		return true
+	})
+	if !supported {
+		return false
+	}
+
+	// A blank import of log/slog becomes a plain one the calls can use; a
+	// dot import has no name to call through.
+	slogName := "slog"
+	existing := findImport(file, "log/slog")
+	if existing != nil && existing.Name != nil {
+		switch existing.Name.Name {
+		case ".":
+			return false
+		case "_":
+			existing.Name = nil
+		default:
+			slogName = existing.Name.Name
+		}
+	}
+
+	if existing != nil {
+		removeImport(file, spec)
+	} else if local == "_" {
+		removeImport(file, spec)
+	} else {
+		spec.Name = nil
+		spec.Path.Value = strconv.Quote("log/slog")
+	}
+
+	for _, sel := range selectors {
+		sel.X.(*ast.Ident).Name = slogName
+	}
+	return true
+}
+
+func removeImport(file *ast.File, target *ast.ImportSpec) {
+decls:
+	for i, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for j, spec := range gen.Specs {
+			if spec != target {
+				continue
+			}
+			gen.Specs = append(gen.Specs[:j], gen.Specs[j+1:]...)
+			if len(gen.Specs) == 0 {
+				file.Decls = append(file.Decls[:i], file.Decls[i+1:]...)
+			}
+			break decls
+		}
+	}
+
+	for i, spec := range file.Imports {
+		if spec == target {
+			file.Imports = append(file.Imports[:i], file.Imports[i+1:]...)
+			break
+		}
+	}
+}
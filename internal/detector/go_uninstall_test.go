@@ -0,0 +1,358 @@
+package detector
+
+import (
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// installTestProject creates a Go module with the logger installed in
+// internal/logdog, logging to a temporary directory.
+func installTestProject(t *testing.T) string {
+	t.Helper()
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, "go.mod"), []byte("module example.com/app\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := DefaultConfig()
+	config.OutputDir = t.TempDir()
+	if err := (&GoLanguage{}).Install(project, config); err != nil {
+		t.Fatal(err)
+	}
+	return project
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRewriteCallSites(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		// want is the rewritten file, or "" if it must be left alone.
+		want string
+	}{
+		{
+			name: "every level",
+			src: `package main
+
+import "example.com/app/internal/logdog"
+
+func main() {
+	logdog.Debug("starting")
+	logdog.Info("listening", "port", 8080)
+	logdog.Warn("slow", "ms", 900)
+	logdog.Error("failed", "err", "boom")
+}
+`,
+			want: `package main
+
+import "log/slog"
+
+func main() {
+	slog.Debug("starting")
+	slog.Info("listening", "port", 8080)
+	slog.Warn("slow", "ms", 900)
+	slog.Error("failed", "err", "boom")
+}
+`,
+		},
+		{
+			name: "aliased import",
+			src: `package main
+
+import lg "example.com/app/internal/logdog"
+
+func main() { lg.Info("hi") }
+`,
+			want: `package main
+
+import "log/slog"
+
+func main() { slog.Info("hi") }
+`,
+		},
+		{
+			name: "existing slog import",
+			src: `package main
+
+import (
+	"log/slog"
+
+	"example.com/app/internal/logdog"
+)
+
+func main() {
+	slog.Info("a")
+	logdog.Info("b")
+}
+`,
+			want: `package main
+
+import (
+	"log/slog"
+)
+
+func main() {
+	slog.Info("a")
+	slog.Info("b")
+}
+`,
+		},
+		{
+			name: "aliased slog import",
+			src: `package main
+
+import (
+	"example.com/app/internal/logdog"
+	s "log/slog"
+)
+
+func main() {
+	s.Info("a")
+	logdog.Warn("b")
+}
+`,
+			want: `package main
+
+import (
+	s "log/slog"
+)
+
+func main() {
+	s.Info("a")
+	s.Warn("b")
+}
+`,
+		},
+		{
+			name: "blank slog import",
+			src: `package main
+
+import (
+	"example.com/app/internal/logdog"
+	_ "log/slog"
+)
+
+func main() { logdog.Error("b") }
+`,
+			want: `package main
+
+import (
+	"log/slog"
+)
+
+func main() { slog.Error("b") }
+`,
+		},
+		{
+			name: "blank logger import",
+			src: `package main
+
+import (
+	"fmt"
+
+	_ "example.com/app/internal/logdog"
+)
+
+func main() { fmt.Println("hi") }
+`,
+			want: `package main
+
+import (
+	"fmt"
+)
+
+func main() { fmt.Println("hi") }
+`,
+		},
+		{
+			name: "dot slog import",
+			src: `package main
+
+import (
+	"example.com/app/internal/logdog"
+	. "log/slog"
+)
+
+func main() {
+	Info("a")
+	logdog.Info("b")
+}
+`,
+		},
+		{
+			name: "function slog lacks",
+			src: `package main
+
+import "example.com/app/internal/logdog"
+
+func main() {
+	logdog.SetLevel(logdog.DEBUG)
+	logdog.Info("b")
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := installTestProject(t)
+			path := filepath.Join(project, "cmd", "main.go")
+			writeTestFile(t, path, tt.src)
+
+			rewritten, remaining, err := (&GoLanguage{}).RewriteCallSites(project)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.want == "" {
+				if len(rewritten) != 0 || len(remaining) != 1 {
+					t.Errorf("rewritten %v, remaining %v, want the file remaining", rewritten, remaining)
+				}
+				if string(got) != tt.src {
+					t.Errorf("file changed to\n%s", got)
+				}
+				return
+			}
+
+			if len(rewritten) != 1 || len(remaining) != 0 {
+				t.Errorf("rewritten %v, remaining %v, want the file rewritten", rewritten, remaining)
+			}
+			if string(got) != tt.want {
+				t.Errorf("rewritten to\n%s\nwant\n%s", got, tt.want)
+			}
+			if _, err := format.Source(got); err != nil {
+				t.Errorf("rewritten file doesn't parse: %v", err)
+			}
+		})
+	}
+}
+
+// legacyLogger is what the logger template generated before it had the
+// marker comment, cut down to the declarations that identify it.
+const legacyLogger = `package logdog
+
+import "sync"
+
+type LogLevel string
+
+type Logger struct {
+	mu       sync.Mutex
+	logLevel LogLevel
+	logDir   string
+}
+
+var defaultLogger *Logger
+
+func Info(message string, args ...interface{}) {}
+`
+
+func TestUninstall(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T) string
+		// kept are the files, relative to the project, that must survive;
+		// removed those that must be gone.
+		kept    []string
+		removed []string
+		wantErr bool
+	}{
+		{
+			name:    "generated package",
+			setup:   installTestProject,
+			kept:    []string{"go.mod"},
+			removed: []string{"internal/logdog", "internal"},
+		},
+		{
+			name: "generated package with other files",
+			setup: func(t *testing.T) string {
+				project := installTestProject(t)
+				writeTestFile(t, filepath.Join(project, "internal", "logdog", "helpers.go"), "package logdog\n")
+				writeTestFile(t, filepath.Join(project, "internal", "config", "config.go"), "package config\n")
+				return project
+			},
+			kept:    []string{"internal/logdog/helpers.go", "internal/config/config.go"},
+			removed: []string{"internal/logdog/logger.go", "internal/logdog/README.md"},
+		},
+		{
+			name: "legacy logger",
+			setup: func(t *testing.T) string {
+				project := t.TempDir()
+				writeTestFile(t, filepath.Join(project, "go.mod"), "module example.com/app\n")
+				writeTestFile(t, filepath.Join(project, "internal", "logdog", "logger.go"), legacyLogger)
+				writeTestFile(t, filepath.Join(project, "internal", "logdog", "README.md"), "# 🐕 Logdog\n")
+				return project
+			},
+			kept:    []string{"go.mod"},
+			removed: []string{"internal"},
+		},
+		{
+			name: "foreign logger",
+			setup: func(t *testing.T) string {
+				project := t.TempDir()
+				writeTestFile(t, filepath.Join(project, "go.mod"), "module example.com/app\n")
+				writeTestFile(t, filepath.Join(project, "internal", "logdog", "logger.go"), "package logdog\n\nfunc Info(message string) {}\n")
+				writeTestFile(t, filepath.Join(project, "internal", "logdog", "README.md"), "# Our logger\n")
+				return project
+			},
+			kept:    []string{"internal/logdog/logger.go", "internal/logdog/README.md"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := tt.setup(t)
+			err := (&GoLanguage{}).Uninstall(project)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Uninstall() error = %v, want error %v", err, tt.wantErr)
+			}
+			for _, rel := range tt.kept {
+				if _, err := os.Stat(filepath.Join(project, rel)); err != nil {
+					t.Errorf("%s is gone, want it kept", rel)
+				}
+			}
+			for _, rel := range tt.removed {
+				if _, err := os.Stat(filepath.Join(project, rel)); !os.IsNotExist(err) {
+					t.Errorf("%s still exists, want it removed", rel)
+				}
+			}
+		})
+	}
+}
+
+func TestIsLegacyLogger(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want bool
+	}{
+		{"baseline template", legacyLogger, true},
+		{"other package name", strings.Replace(legacyLogger, "package logdog", "package applog", 1), false},
+		{"no logDir field", strings.Replace(legacyLogger, "logDir   string", "dir string", 1), false},
+		{"no defaultLogger", strings.Replace(legacyLogger, "var defaultLogger *Logger", "", 1), false},
+		{"user package", "package logdog\n\ntype LogLevel int\n", false},
+		{"not Go", "not go at all", false},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "logger.go")
+		writeTestFile(t, path, tt.src)
+		if got := isLegacyLogger(path); got != tt.want {
+			t.Errorf("%s: isLegacyLogger() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -4,14 +4,45 @@ type Language interface {
 	Name() string
 	Detect(projectPath string) bool
 	Install(projectPath string, config Config) error
+	PlanInstall(projectPath string, config Config) (*InstallPlan, error)
+	ImportPath(projectPath string, config Config) (string, error)
+	Uninstall(projectPath string) error
+	CallSites(projectPath string) ([]string, error)
+	RewriteCallSites(projectPath string) (rewritten []string, remaining []string, err error)
+	LoggerStatus(projectPath string, config Config) (LoggerStatus, error)
 	GetLogPaths(projectPath string) []string
 }
 
 type Config struct {
-	LogLevel   string `json:"log_level"`
-	OutputDir  string `json:"output_dir"`
-	MaxFiles   int    `json:"max_files"`
-	DateFormat string `json:"date_format"`
+	LogLevel string `json:"log_level"`
+	// OutputDir is where logs are written. Empty means ~/logdog/<project>;
+	// relative paths are resolved against the project root.
+	OutputDir string `json:"output_dir"`
+	// MaxFiles is how many daily files "clear old logs" keeps at most,
+	// dropping the oldest beyond that.
+	MaxFiles int `json:"max_files"`
+	// Retention is how long "clear old logs" keeps entries of each level.
+	Retention RetentionPolicy `json:"retention"`
+	// RetentionDays is the single retention period of older configs. It
+	// is read as a policy keeping every level that long and not written
+	// back.
+	RetentionDays int `json:"retention_days,omitempty"`
+	// PackagePath is where the generated package goes, relative to the
+	// project root (e.g. "internal/logdog" or "pkg/log").
+	PackagePath string `json:"package_path"`
+	PackageName string `json:"package_name"`
+}
+
+// DefaultConfig is the logger configuration used when nothing else is set.
+func DefaultConfig() Config {
+	return Config{
+		LogLevel:    "INFO",
+		OutputDir:   "",
+		MaxFiles:    30,
+		Retention:   DefaultRetention(),
+		PackagePath: "internal/logdog",
+		PackageName: "logdog",
+	}
 }
 
 var SupportedLanguages = []Language{
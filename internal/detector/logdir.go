@@ -0,0 +1,356 @@
+package detector
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+// LogRoot returns the directory holding every project's logs: the log root
+// from the global config, or ~/logdog.
+func LogRoot() (string, error) {
+	if root := globalConfig().LogRoot; root != "" {
+		return expandHome(root)
+	}
+	usr, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(usr.HomeDir, "logdog"), nil
+}
+
+// expandHome resolves a leading ~ to the user's home directory.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return filepath.Clean(path), nil
+	}
+	usr, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(usr.HomeDir, path[1:]), nil
+}
+
+// ProjectLogDir returns ~/logdog/<project-name> for the given project path.
+func ProjectLogDir(projectPath string) (string, error) {
+	root, err := LogRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, filepath.Base(projectPath)), nil
+}
+
+// ResolveLogDir returns where a project's logs are written: the configured
+// output directory if there is one, otherwise ~/logdog/<project-name>.
+func ResolveLogDir(projectPath string, config Config) (string, error) {
+	dir := config.OutputDir
+	switch {
+	case dir == "":
+		return ProjectLogDir(projectPath)
+	case dir == "~" || strings.HasPrefix(dir, "~/"):
+		return expandHome(dir)
+	case !filepath.IsAbs(dir):
+		dir = filepath.Join(projectPath, dir)
+	}
+	return filepath.Clean(dir), nil
+}
+
+// CheckOutputDir reports whether dir is a safe output directory for the
+// project at projectPath, which may be empty if there is none. Since a
+// project's log directory can be deleted along with the logger, it must
+// not be the project itself, the home directory, the log root or any
+// directory containing one of them.
+func CheckOutputDir(projectPath, dir string) error {
+	if dir == "" {
+		return nil
+	}
+	if clean := filepath.Clean(dir); !filepath.IsAbs(clean) && clean != "~" && !strings.HasPrefix(clean, "~/") {
+		if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("invalid output directory %q: a relative path must be a subdirectory of the project", dir)
+		}
+		return nil
+	}
+
+	resolved, err := ResolveLogDir(projectPath, Config{OutputDir: dir})
+	if err != nil {
+		return err
+	}
+	protected := []string{projectPath}
+	if usr, err := user.Current(); err == nil {
+		protected = append(protected, usr.HomeDir)
+	}
+	if root, err := LogRoot(); err == nil {
+		protected = append(protected, root)
+	}
+	for _, path := range protected {
+		if path != "" && isWithin(resolved, filepath.Clean(path)) {
+			return fmt.Errorf("invalid output directory %q: it is or contains %s", dir, path)
+		}
+	}
+	return nil
+}
+
+// isWithin reports whether path is dir or inside it.
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// checkRemovable refuses to let a log directory be deleted unless it is a
+// project directory under the log root, or holds nothing but daily log
+// files, so that a misconfigured output directory can't take anything
+// else with it.
+func checkRemovable(logDir string) error {
+	logDir, err := filepath.Abs(logDir)
+	if err != nil {
+		return err
+	}
+	if root, err := LogRoot(); err == nil && logDir != root && isWithin(root, logDir) {
+		return nil
+	}
+
+	entries, err := os.ReadDir(logDir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", logDir, err)
+	}
+	for _, entry := range entries {
+		if _, _, ok := logs.ParseDailyFileName(entry.Name()); !ok || entry.IsDir() {
+			return fmt.Errorf("refusing to remove %s: it isn't a project under the log root and holds %s, which isn't a log file", logDir, entry.Name())
+		}
+	}
+	return nil
+}
+
+// RemoveLogDir deletes a project's log directory and everything in it.
+func RemoveLogDir(logDir string) error {
+	if err := checkRemovable(logDir); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(logDir); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", logDir, err)
+	}
+	return nil
+}
+
+// ArchiveLogDir packs a project's log directory into a tarball next to it
+// (<dir>-YYYYMMDD-HHMMSS.tar.gz) and removes the original directory.
+// It returns the path of the written archive.
+func ArchiveLogDir(logDir string) (string, error) {
+	info, err := os.Stat(logDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", logDir, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", logDir)
+	}
+	if err := checkRemovable(logDir); err != nil {
+		return "", err
+	}
+
+	archivePath := fmt.Sprintf("%s-%s.tar.gz", filepath.Clean(logDir), time.Now().Format("20060102-150405"))
+	if err := writeTarball(logDir, archivePath); err != nil {
+		os.Remove(archivePath)
+		return "", fmt.Errorf("failed to archive %s: %w", logDir, err)
+	}
+
+	if err := RemoveLogDir(logDir); err != nil {
+		return archivePath, err
+	}
+	return archivePath, nil
+}
+
+// RenameLogDir renames a project's log directory to name, in the same
+// parent directory, along with the project name in its daily file names.
+// It returns the new directory.
+func RenameLogDir(logDir, name string) (string, error) {
+	if err := validateProjectName(name); err != nil {
+		return "", err
+	}
+	newDir := filepath.Join(filepath.Dir(filepath.Clean(logDir)), name)
+	if _, err := os.Stat(newDir); err == nil {
+		return "", fmt.Errorf("%s already exists", newDir)
+	}
+	if err := os.Rename(logDir, newDir); err != nil {
+		return "", fmt.Errorf("failed to rename %s: %w", logDir, err)
+	}
+
+	entries, err := os.ReadDir(newDir)
+	if err != nil {
+		return newDir, fmt.Errorf("failed to read %s: %w", newDir, err)
+	}
+	for _, entry := range entries {
+		_, day, ok := logs.ParseDailyFileName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(newDir, entry.Name())
+		target := filepath.Join(newDir, logs.DailyFileName(name, day))
+		if path == target {
+			continue
+		}
+		if _, err := os.Stat(target); err == nil {
+			// Two prefixes for one day; merge them rather than overwrite.
+			if err := mergeLogFile(path, target); err != nil {
+				return newDir, err
+			}
+			continue
+		}
+		if err := os.Rename(path, target); err != nil {
+			return newDir, fmt.Errorf("failed to rename %s: %w", path, err)
+		}
+	}
+	return newDir, nil
+}
+
+// MergeLogDirs moves every file of the log directory src into the one of
+// the project name next to it and removes src, e.g. after a repo rename
+// split a project's logs in two. Daily files are renamed for that project,
+// and a day both have is merged into one file in time order.
+func MergeLogDirs(src, name string) error {
+	if err := validateProjectName(name); err != nil {
+		return err
+	}
+	src = filepath.Clean(src)
+	dst := filepath.Join(filepath.Dir(src), name)
+	if src == dst {
+		return fmt.Errorf("can't merge %s into itself", src)
+	}
+	if info, err := os.Stat(dst); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a log directory", dst)
+	}
+	project := filepath.Base(dst)
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	for _, entry := range entries {
+		path := filepath.Join(src, entry.Name())
+		name := entry.Name()
+		if _, day, ok := logs.ParseDailyFileName(name); ok && !entry.IsDir() {
+			name = logs.DailyFileName(project, day)
+		}
+		target := filepath.Join(dst, name)
+
+		if _, err := os.Stat(target); err == nil {
+			if entry.IsDir() || filepath.Ext(name) != ".json" {
+				return fmt.Errorf("%s already exists", target)
+			}
+			if err := mergeLogFile(path, target); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.Rename(path, target); err != nil {
+			return fmt.Errorf("failed to move %s: %w", path, err)
+		}
+	}
+	return RemoveLogDir(src)
+}
+
+// mergeLogFile merges the entries of the log file src into dst in time
+// order and removes src.
+func mergeLogFile(src, dst string) error {
+	srcEntries, err := logs.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	dstEntries, err := logs.ReadFile(dst)
+	if err != nil {
+		return err
+	}
+
+	if err := writeEntries(dst, logs.Merge(dstEntries, srcEntries)); err != nil {
+		return err
+	}
+	if err := os.Remove(src); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", src, err)
+	}
+	return nil
+}
+
+// writeEntries replaces the log file at path with entries, written through
+// a temporary file so it is never left half written.
+func writeEntries(path string, entries []logs.Entry) error {
+	var b strings.Builder
+	for _, entry := range entries {
+		b.WriteString(entry.Raw)
+		b.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// validateProjectName checks that name can be a directory under the log
+// root.
+func validateProjectName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid project name %q", name)
+	}
+	return nil
+}
+
+func writeTarball(srcDir, archivePath string) error {
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+
+	base := filepath.Dir(filepath.Clean(srcDir))
+	err = filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
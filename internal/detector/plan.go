@@ -0,0 +1,159 @@
+package detector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InstallPlan describes everything an install would touch on disk. It is
+// built without side effects so it can be reviewed before Apply is called.
+type InstallPlan struct {
+	Language    string
+	ProjectPath string
+	LogDir      string
+	ImportPath  string
+	Dirs        []PlannedDir
+	Files       []PlannedFile
+}
+
+type PlannedDir struct {
+	Path   string
+	Exists bool
+}
+
+type PlannedFile struct {
+	Path     string
+	Content  string
+	Exists   bool
+	Previous string
+	// Template is the override file the content was rendered from, or
+	// empty for the built-in template.
+	Template string
+}
+
+func planDir(path string) PlannedDir {
+	info, err := os.Stat(path)
+	return PlannedDir{Path: path, Exists: err == nil && info.IsDir()}
+}
+
+func planFile(path, content, template string) PlannedFile {
+	file := PlannedFile{Path: path, Content: content, Template: template}
+	if previous, err := os.ReadFile(path); err == nil {
+		file.Exists = true
+		file.Previous = string(previous)
+	}
+	return file
+}
+
+// Apply creates the planned directories and writes the planned files.
+func (p *InstallPlan) Apply() error {
+	for _, dir := range p.Dirs {
+		if err := os.MkdirAll(dir.Path, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir.Path, err)
+		}
+	}
+
+	for _, file := range p.Files {
+		if err := os.WriteFile(file.Path, []byte(file.Content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// Summary lists the directories and files the plan would create or
+// overwrite, and where logs will be written.
+func (p *InstallPlan) Summary() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Install plan for %s project in %s\n", p.Language, p.ProjectPath)
+	fmt.Fprintf(&b, "Log directory: %s\n", p.LogDir)
+	fmt.Fprintf(&b, "Import path:   %s\n\n", p.ImportPath)
+
+	b.WriteString("Directories:\n")
+	for _, dir := range p.Dirs {
+		action := "create"
+		if dir.Exists {
+			action = "exists"
+		}
+		fmt.Fprintf(&b, "  %-9s %s\n", action, dir.Path)
+	}
+
+	b.WriteString("\nFiles:\n")
+	for _, file := range p.Files {
+		action := "create"
+		if file.Exists {
+			action = "overwrite"
+		}
+		fmt.Fprintf(&b, "  %-9s %s", action, p.relPath(file.Path))
+		if file.Template != "" {
+			fmt.Fprintf(&b, " (template: %s)", file.Template)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// Patch renders the planned files as a unified diff relative to the project
+// root, suitable for review or for `git apply`.
+func (p *InstallPlan) Patch() string {
+	var b strings.Builder
+
+	for _, file := range p.Files {
+		rel := filepath.ToSlash(p.relPath(file.Path))
+		newLines := len(splitLines(file.Content))
+
+		fmt.Fprintf(&b, "diff --git a/%s b/%s\n", rel, rel)
+		if file.Exists {
+			fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", rel, rel)
+			fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(len(splitLines(file.Previous))), hunkRange(newLines))
+			writeDiffLines(&b, "-", file.Previous)
+		} else {
+			b.WriteString("new file mode 100644\n")
+			fmt.Fprintf(&b, "--- /dev/null\n+++ b/%s\n", rel)
+			fmt.Fprintf(&b, "@@ -0,0 +%s @@\n", hunkRange(newLines))
+		}
+		writeDiffLines(&b, "+", file.Content)
+	}
+
+	return b.String()
+}
+
+// String is the full dry-run report: the summary followed by the patch.
+func (p *InstallPlan) String() string {
+	return p.Summary() + "\n" + p.Patch()
+}
+
+func (p *InstallPlan) relPath(path string) string {
+	if rel, err := filepath.Rel(p.ProjectPath, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+func writeDiffLines(b *strings.Builder, prefix, content string) {
+	for _, line := range splitLines(content) {
+		b.WriteString(prefix + line + "\n")
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		b.WriteString("\\ No newline at end of file\n")
+	}
+}
+
+func hunkRange(n int) string {
+	if n == 0 {
+		return "0,0"
+	}
+	return fmt.Sprintf("1,%d", n)
+}
@@ -0,0 +1,338 @@
+package detector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+// RetentionPolicy is how many days entries of each level are kept before
+// "clear old logs" removes them.
+type RetentionPolicy struct {
+	Error int `json:"error"`
+	Warn  int `json:"warn"`
+	Info  int `json:"info"`
+	Debug int `json:"debug"`
+}
+
+// DefaultRetention keeps errors for months and debug output for a couple
+// of days.
+func DefaultRetention() RetentionPolicy {
+	return RetentionPolicy{Error: 90, Warn: 30, Info: 14, Debug: 2}
+}
+
+// UniformRetention keeps every level for the same number of days, the way
+// the single retention_days of older configs did.
+func UniformRetention(days int) RetentionPolicy {
+	return RetentionPolicy{Error: days, Warn: days, Info: days, Debug: days}
+}
+
+// Days returns how long entries of level are kept. Entries with an unknown
+// level, or lines that aren't entries at all, are kept as long as anything.
+func (p RetentionPolicy) Days(level string) int {
+	switch strings.ToUpper(level) {
+	case "ERROR":
+		return p.Error
+	case "WARN":
+		return p.Warn
+	case "INFO":
+		return p.Info
+	case "DEBUG":
+		return p.Debug
+	}
+	return p.Longest()
+}
+
+// Longest and Shortest are the longest and shortest periods of any level.
+func (p RetentionPolicy) Longest() int {
+	return max(p.Error, p.Warn, p.Info, p.Debug)
+}
+
+func (p RetentionPolicy) Shortest() int {
+	return min(p.Error, p.Warn, p.Info, p.Debug)
+}
+
+// Shift adds delta days to every level, as long as all stay within 1 to
+// limit days.
+func (p RetentionPolicy) Shift(delta, limit int) RetentionPolicy {
+	shifted := RetentionPolicy{Error: p.Error + delta, Warn: p.Warn + delta, Info: p.Info + delta, Debug: p.Debug + delta}
+	if shifted.Shortest() < 1 || shifted.Longest() > limit {
+		return p
+	}
+	return shifted
+}
+
+// String lists the levels with their periods, e.g. "ERROR 90d, WARN 30d,
+// INFO 14d, DEBUG 2d".
+func (p RetentionPolicy) String() string {
+	return fmt.Sprintf("ERROR %dd, WARN %dd, INFO %dd, DEBUG %dd", p.Error, p.Warn, p.Info, p.Debug)
+}
+
+// fillRetention fills the levels a config leaves unset: from the single
+// retention_days of older configs if it has one, else from defaults.
+func (c *Config) fillRetention(defaults RetentionPolicy) {
+	if c.RetentionDays > 0 {
+		defaults = UniformRetention(c.RetentionDays)
+		c.RetentionDays = 0
+	}
+	c.Retention = c.Retention.orDefaults(defaults)
+}
+
+// orDefaults fills the levels left unset from fallback.
+func (p RetentionPolicy) orDefaults(fallback RetentionPolicy) RetentionPolicy {
+	if p.Error < 1 {
+		p.Error = fallback.Error
+	}
+	if p.Warn < 1 {
+		p.Warn = fallback.Warn
+	}
+	if p.Info < 1 {
+		p.Info = fallback.Info
+	}
+	if p.Debug < 1 {
+		p.Debug = fallback.Debug
+	}
+	return p
+}
+
+// ParseRetention reads levels and days from text such as "ERROR 90 WARN 30"
+// or "error=90, debug=2" on top of base, so levels not mentioned keep their
+// period.
+func ParseRetention(text string, base RetentionPolicy) (RetentionPolicy, error) {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '=' || r == ':'
+	})
+	if len(words)%2 != 0 {
+		return base, fmt.Errorf("expected levels followed by days, e.g. ERROR 90 WARN 30")
+	}
+
+	policy := base
+	for i := 0; i < len(words); i += 2 {
+		days, err := strconv.Atoi(strings.TrimSuffix(words[i+1], "d"))
+		if err != nil || days < 1 {
+			return base, fmt.Errorf("%q is not a positive number of days", words[i+1])
+		}
+		switch strings.ToUpper(words[i]) {
+		case "ERROR":
+			policy.Error = days
+		case "WARN":
+			policy.Warn = days
+		case "INFO":
+			policy.Info = days
+		case "DEBUG":
+			policy.Debug = days
+		default:
+			return base, fmt.Errorf("unknown level %q, expected ERROR, WARN, INFO or DEBUG", words[i])
+		}
+	}
+	return policy, nil
+}
+
+// RetentionPlan is what applying a retention policy to log files would
+// remove. It is built without touching disk so it can be confirmed before
+// Apply is called.
+type RetentionPlan struct {
+	Policy RetentionPolicy
+	// MaxFiles is how many daily files are kept at most, 0 for no limit.
+	MaxFiles int
+	Now      time.Time
+	// Files are the files with expired entries.
+	Files []RetentionFile
+}
+
+// RetentionFile is a log file with entries past their level's period.
+type RetentionFile struct {
+	Path    string
+	Entries int
+	Expired map[string]int
+	// Dropped is set for a daily file older than the newest MaxFiles,
+	// which goes whole.
+	Dropped bool
+}
+
+// Removed is how many of the file's entries would go.
+func (f RetentionFile) Removed() int {
+	n := 0
+	for _, count := range f.Expired {
+		n += count
+	}
+	return n
+}
+
+// PlanRetention reads the log files and counts the entries of each that
+// are older than their level's period, and every entry of the daily files
+// older than the newest maxFiles (0 for no limit). Files too recent for
+// anything in them to go aren't read.
+func PlanRetention(paths []string, policy RetentionPolicy, maxFiles int, now time.Time) (*RetentionPlan, error) {
+	plan := &RetentionPlan{Policy: policy, MaxFiles: maxFiles, Now: now}
+	dropped := plan.droppedFiles(paths)
+	for _, path := range paths {
+		if _, day, ok := logs.ParseDailyFileName(filepath.Base(path)); ok && !dropped[path] && !plan.expired(policy.Shortest(), day.AddDate(0, 0, 1)) {
+			continue
+		}
+
+		file := RetentionFile{Path: path, Expired: map[string]int{}, Dropped: dropped[path]}
+		entries, err := logs.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		file.Entries = len(entries)
+		for _, entry := range entries {
+			if file.Dropped || plan.entryExpired(path, entry) {
+				file.Expired[retentionLevel(entry)]++
+			}
+		}
+		if file.Removed() > 0 || file.Dropped {
+			plan.Files = append(plan.Files, file)
+		}
+	}
+	return plan, nil
+}
+
+// droppedFiles are the daily files beyond the newest MaxFiles.
+func (p *RetentionPlan) droppedFiles(paths []string) map[string]bool {
+	type daily struct {
+		path string
+		day  time.Time
+	}
+	var files []daily
+	for _, path := range paths {
+		if _, day, ok := logs.ParseDailyFileName(filepath.Base(path)); ok {
+			files = append(files, daily{path, day})
+		}
+	}
+	if p.MaxFiles < 1 || len(files) <= p.MaxFiles {
+		return nil
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].day.After(files[j].day) })
+	dropped := map[string]bool{}
+	for _, file := range files[p.MaxFiles:] {
+		dropped[file.path] = true
+	}
+	return dropped
+}
+
+// expired reports whether something from time at is older than days.
+func (p *RetentionPlan) expired(days int, at time.Time) bool {
+	return at.Before(p.Now.AddDate(0, 0, -days))
+}
+
+// entryExpired reports whether an entry of the file at path is past its
+// level's period. Entries without a timestamp take the file's day, or are
+// kept if it has none.
+func (p *RetentionPlan) entryExpired(path string, entry logs.Entry) bool {
+	at := entry.Time
+	if at.IsZero() {
+		_, day, ok := logs.ParseDailyFileName(filepath.Base(path))
+		if !ok {
+			return false
+		}
+		at = day
+	}
+	return p.expired(p.Policy.Days(retentionLevel(entry)), at)
+}
+
+// retentionLevel is the level an entry is kept by, OTHER for lines that
+// aren't entries or have no known level.
+func retentionLevel(entry logs.Entry) string {
+	level := strings.ToUpper(entry.Level)
+	switch level {
+	case "ERROR", "WARN", "INFO", "DEBUG":
+		if entry.Valid {
+			return level
+		}
+	}
+	return "OTHER"
+}
+
+// Removed is how many entries the plan removes, in total and per level.
+func (p *RetentionPlan) Removed() (int, map[string]int) {
+	total, byLevel := 0, map[string]int{}
+	for _, file := range p.Files {
+		for level, count := range file.Expired {
+			byLevel[level] += count
+			total += count
+		}
+	}
+	return total, byLevel
+}
+
+// Emptied is how many files would have no entries left and be deleted.
+func (p *RetentionPlan) Emptied() int {
+	n := 0
+	for _, file := range p.Files {
+		if file.Removed() == file.Entries {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary describes what the plan removes in one line, e.g. "1200
+// entries (DEBUG 1000, INFO 200) from 5 files".
+func (p *RetentionPlan) Summary() string {
+	total, byLevel := p.Removed()
+	levels := make([]string, 0, len(byLevel))
+	for level := range byLevel {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		if byLevel[levels[i]] != byLevel[levels[j]] {
+			return byLevel[levels[i]] > byLevel[levels[j]]
+		}
+		return levels[i] < levels[j]
+	})
+	parts := make([]string, len(levels))
+	for i, level := range levels {
+		parts[i] = fmt.Sprintf("%s %d", level, byLevel[level])
+	}
+	return fmt.Sprintf("%d entries (%s) from %d files", total, strings.Join(parts, ", "), len(p.Files))
+}
+
+// Apply rewrites each planned file without its expired entries, deleting
+// dropped files and files that end up empty.
+func (p *RetentionPlan) Apply() error {
+	for _, file := range p.Files {
+		if err := p.applyFile(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *RetentionPlan) applyFile(file RetentionFile) error {
+	path := file.Path
+	if file.Dropped {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", path, err)
+		}
+		return nil
+	}
+
+	entries, err := logs.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var kept []logs.Entry
+	for _, entry := range entries {
+		if !p.entryExpired(path, entry) {
+			kept = append(kept, entry)
+		}
+	}
+
+	if len(kept) == 0 {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", path, err)
+		}
+		return nil
+	}
+	return writeEntries(path, kept)
+}
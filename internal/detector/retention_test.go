@@ -0,0 +1,256 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+func TestParseRetention(t *testing.T) {
+	base := DefaultRetention()
+	tests := []struct {
+		text string
+		want RetentionPolicy
+	}{
+		{"", base},
+		{"ERROR 120", RetentionPolicy{Error: 120, Warn: 30, Info: 14, Debug: 2}},
+		{"error=60, debug=1", RetentionPolicy{Error: 60, Warn: 30, Info: 14, Debug: 1}},
+		{"warn: 7 info: 7", RetentionPolicy{Error: 90, Warn: 7, Info: 7, Debug: 2}},
+		{base.String(), base},
+		{"ERROR 1d, WARN 2d, INFO 3d, DEBUG 4d", RetentionPolicy{Error: 1, Warn: 2, Info: 3, Debug: 4}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRetention(tt.text, base)
+		if err != nil {
+			t.Errorf("ParseRetention(%q): %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRetention(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+
+	for _, text := range []string{"ERROR", "ERROR ninety", "ERROR 0", "ERROR -5", "FATAL 10", "90 ERROR"} {
+		got, err := ParseRetention(text, base)
+		if err == nil {
+			t.Errorf("ParseRetention(%q) succeeded, want an error", text)
+		}
+		if got != base {
+			t.Errorf("ParseRetention(%q) = %v on error, want the base policy", text, got)
+		}
+	}
+}
+
+func TestRetentionPolicyShift(t *testing.T) {
+	policy := RetentionPolicy{Error: 90, Warn: 30, Info: 14, Debug: 1}
+	tests := []struct {
+		delta int
+		want  RetentionPolicy
+	}{
+		{1, RetentionPolicy{Error: 91, Warn: 31, Info: 15, Debug: 2}},
+		{-1, policy},
+		{275, RetentionPolicy{Error: 365, Warn: 305, Info: 289, Debug: 276}},
+		{276, policy},
+	}
+	for _, tt := range tests {
+		if got := policy.Shift(tt.delta, 365); got != tt.want {
+			t.Errorf("Shift(%d) = %v, want %v", tt.delta, got, tt.want)
+		}
+	}
+}
+
+func TestLegacyRetentionDays(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	globalCached = nil
+	t.Cleanup(func() { globalCached = nil })
+
+	projectDir := t.TempDir()
+	tests := []struct {
+		config string
+		want   RetentionPolicy
+	}{
+		{`{}`, DefaultRetention()},
+		{`{"retention_days": 7}`, UniformRetention(7)},
+		{`{"retention": {"debug": 1}}`, RetentionPolicy{Error: 90, Warn: 30, Info: 14, Debug: 1}},
+		{`{"retention_days": 10, "retention": {"error": 60}}`, RetentionPolicy{Error: 60, Warn: 10, Info: 10, Debug: 10}},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(filepath.Join(projectDir, ProjectConfigFile), []byte(tt.config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		config, err := LoadProjectConfig(projectDir)
+		if err != nil {
+			t.Errorf("LoadProjectConfig(%s): %v", tt.config, err)
+			continue
+		}
+		if config.Retention != tt.want {
+			t.Errorf("LoadProjectConfig(%s).Retention = %v, want %v", tt.config, config.Retention, tt.want)
+		}
+		if config.RetentionDays != 0 {
+			t.Errorf("LoadProjectConfig(%s) kept retention_days %d", tt.config, config.RetentionDays)
+		}
+
+		data, _ := json.Marshal(config)
+		if strings.Contains(string(data), "retention_days") {
+			t.Errorf("config from %s is saved with retention_days: %s", tt.config, data)
+		}
+	}
+}
+
+// retentionDay writes a daily log file for the day days before now with an
+// entry at noon for each level, or a line that isn't JSON for "".
+func retentionDay(t *testing.T, dir string, now time.Time, days int, levels ...string) string {
+	t.Helper()
+	day := now.AddDate(0, 0, -days)
+	var lines []string
+	for i, level := range levels {
+		at := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, i, 0, time.Local)
+		if level == "" {
+			lines = append(lines, fmt.Sprintf("not json %d", i))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf(`{"timestamp": %q, "level": %q, "message": "%s %d"}`, at.Format("2006-01-02 15:04:05"), level, level, i))
+	}
+	path := filepath.Join(dir, logs.DailyFileName("app", day))
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRetentionPlan(t *testing.T) {
+	now := time.Date(2026, 10, 18, 18, 0, 0, 0, time.Local)
+	dir := t.TempDir()
+
+	today := retentionDay(t, dir, now, 0, "DEBUG", "INFO")
+	recent := retentionDay(t, dir, now, 1, "DEBUG")
+	week := retentionDay(t, dir, now, 7, "ERROR", "WARN", "INFO", "DEBUG", "DEBUG")
+	month := retentionDay(t, dir, now, 45, "ERROR", "WARN", "INFO", "DEBUG", "", "TRACE")
+	emptied := retentionDay(t, dir, now, 20, "INFO", "DEBUG")
+	ancient := retentionDay(t, dir, now, 200, "ERROR", "")
+
+	plan, err := PlanRetention([]string{today, recent, week, month, emptied, ancient}, DefaultRetention(), 0, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantExpired := map[string]map[string]int{
+		week:    {"DEBUG": 2},
+		month:   {"WARN": 1, "INFO": 1, "DEBUG": 1},
+		emptied: {"INFO": 1, "DEBUG": 1},
+		ancient: {"ERROR": 1, "OTHER": 1},
+	}
+	if len(plan.Files) != len(wantExpired) {
+		t.Fatalf("plan has %d files, want %d", len(plan.Files), len(wantExpired))
+	}
+	for _, file := range plan.Files {
+		want, ok := wantExpired[file.Path]
+		if !ok {
+			t.Errorf("plan includes %s", filepath.Base(file.Path))
+			continue
+		}
+		if fmt.Sprint(file.Expired) != fmt.Sprint(want) {
+			t.Errorf("%s: expired %v, want %v", filepath.Base(file.Path), file.Expired, want)
+		}
+	}
+
+	if total, _ := plan.Removed(); total != 9 {
+		t.Errorf("Removed() = %d, want 9", total)
+	}
+	if got := plan.Emptied(); got != 2 {
+		t.Errorf("Emptied() = %d, want 2", got)
+	}
+	if got, want := plan.Summary(), "9 entries (DEBUG 4, INFO 2, ERROR 1, OTHER 1, WARN 1) from 4 files"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	wantMessages := map[string][]string{
+		today:   {"DEBUG 0", "INFO 1"},
+		recent:  {"DEBUG 0"},
+		week:    {"ERROR 0", "WARN 1", "INFO 2"},
+		month:   {"ERROR 0", "", "TRACE 5"},
+		emptied: nil,
+		ancient: nil,
+	}
+	for path, want := range wantMessages {
+		entries, err := logs.ReadFile(path)
+		if want == nil {
+			if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+				t.Errorf("%s still exists, want it deleted", filepath.Base(path))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", filepath.Base(path), err)
+			continue
+		}
+		var got []string
+		for _, entry := range entries {
+			got = append(got, entry.Message)
+		}
+		if strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("%s keeps %q, want %q", filepath.Base(path), got, want)
+		}
+	}
+
+	again, err := PlanRetention([]string{today, recent, week, month}, DefaultRetention(), 0, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Files) != 0 {
+		t.Errorf("a second plan removes %s, want nothing", again.Summary())
+	}
+}
+
+func TestRetentionMaxFiles(t *testing.T) {
+	now := time.Date(2026, 10, 18, 18, 0, 0, 0, time.Local)
+	dir := t.TempDir()
+
+	var paths []string
+	for days := 0; days < 5; days++ {
+		paths = append(paths, retentionDay(t, dir, now, days, "ERROR", "INFO"))
+	}
+	empty := filepath.Join(dir, logs.DailyFileName("app", now.AddDate(0, 0, -5)))
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	paths = append(paths, empty)
+
+	plan, err := PlanRetention(paths, DefaultRetention(), 3, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := plan.Summary(), "4 entries (ERROR 2, INFO 2) from 3 files"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	if got := plan.Emptied(); got != 3 {
+		t.Errorf("Emptied() = %d, want 3", got)
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	for i, path := range paths {
+		_, err := os.Stat(path)
+		if kept := i < 3; kept != (err == nil) {
+			t.Errorf("%s: kept %v, want %v", filepath.Base(path), err == nil, kept)
+		}
+	}
+
+	unlimited, err := PlanRetention(paths[:3], DefaultRetention(), 0, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unlimited.Files) != 0 {
+		t.Errorf("without a limit the plan removes %s, want nothing", unlimited.Summary())
+	}
+}
@@ -0,0 +1,110 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// State is what the TUI remembers between runs, stored in
+// $XDG_CONFIG_HOME/logdog/state.json next to the global config. Unlike the
+// config it changes as logdog is used and isn't meant to be edited.
+type State struct {
+	// Projects holds what is known about each project, keyed by the name of
+	// the project's log directory.
+	Projects map[string]ProjectState `json:"projects,omitempty"`
+}
+
+// ProjectState is what is known about a project's logs: where its source
+// is and the user's last visit.
+type ProjectState struct {
+	// Source is the project directory the logs come from, once logdog has
+	// been run or installed there.
+	Source     string    `json:"source,omitempty"`
+	LastViewed time.Time `json:"last_viewed"`
+	// File is the log file that was open, Line the raw line of the entry
+	// under the cursor and Entry its index among all the file's entries,
+	// which tells apart identical lines.
+	File  string `json:"file,omitempty"`
+	Line  string `json:"line,omitempty"`
+	Entry int    `json:"entry,omitempty"`
+}
+
+// RememberSource records projectPath as the source of the logs in logDir,
+// if logDir is a project directory under the log root. It reports whether
+// anything changed.
+func (s *State) RememberSource(logDir, projectPath string) bool {
+	root, err := LogRoot()
+	if err != nil || filepath.Dir(filepath.Clean(logDir)) != root {
+		return false
+	}
+	name := filepath.Base(logDir)
+	project := s.Projects[name]
+	if project.Source == projectPath {
+		return false
+	}
+	project.Source = projectPath
+
+	projects := make(map[string]ProjectState, len(s.Projects)+1)
+	for name, p := range s.Projects {
+		projects[name] = p
+	}
+	projects[name] = project
+	s.Projects = projects
+	return true
+}
+
+// StatePath returns the location of the state file.
+func StatePath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "state.json"), nil
+}
+
+// LoadState reads the state file. A missing file is not an error.
+func LoadState() (State, error) {
+	var state State
+
+	path, err := StatePath()
+	if err != nil {
+		return state, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return State{}, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// SaveState writes the state file, creating its directory if needed.
+func SaveState(state State) error {
+	path, err := StatePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package detector
+
+import "path/filepath"
+
+// LoggerStatus is whether a project's generated logger is installed and
+// matches what installing it again would write.
+type LoggerStatus int
+
+const (
+	LoggerMissing LoggerStatus = iota
+	LoggerCurrent
+	LoggerOutdated
+)
+
+func (s LoggerStatus) String() string {
+	switch s {
+	case LoggerCurrent:
+		return "installed"
+	case LoggerOutdated:
+		return "outdated"
+	default:
+		return "not installed"
+	}
+}
+
+// LoggerStatus finds the installed logger package and renders it again
+// where it is, from the project's config and templates. Any file that
+// would change makes it outdated.
+func (g *GoLanguage) LoggerStatus(projectPath string, config Config) (LoggerStatus, error) {
+	dir, err := g.findLoggerDir(projectPath)
+	if err != nil {
+		return LoggerMissing, nil
+	}
+	rel, err := filepath.Rel(projectPath, dir)
+	if err != nil {
+		return LoggerMissing, err
+	}
+	config.PackagePath = filepath.ToSlash(rel)
+	if name := installedPackageName(dir); name != "" {
+		config.PackageName = name
+	}
+
+	plan, err := g.PlanInstall(projectPath, config)
+	if err != nil {
+		return LoggerMissing, err
+	}
+	for _, file := range plan.Files {
+		if !file.Exists || file.Previous != file.Content {
+			return LoggerOutdated, nil
+		}
+	}
+	return LoggerCurrent, nil
+}
@@ -0,0 +1,90 @@
+package detector
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is what the logger and README templates are rendered with,
+// both the built-in ones and user overrides.
+type TemplateData struct {
+	Config     Config
+	ImportPath string
+	Project    ProjectInfo
+}
+
+// ProjectInfo describes the project a logger is being generated for.
+type ProjectInfo struct {
+	Name     string
+	Path     string
+	Module   string
+	Language string
+	LogDir   string
+	// LogName is what the daily log files are named after, the last
+	// element of LogDir.
+	LogName string
+}
+
+// ConfigDir returns logdog's configuration directory,
+// $XDG_CONFIG_HOME/logdog or ~/.config/logdog.
+func ConfigDir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "logdog"), nil
+	}
+	usr, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(usr.HomeDir, ".config", "logdog"), nil
+}
+
+// templateDirs lists the directories searched for template overrides, most
+// specific first: the project's .logdog/templates, then the user's
+// ~/.config/logdog/templates/<language>.
+func templateDirs(projectPath, language string) []string {
+	dirs := []string{filepath.Join(projectPath, ".logdog", "templates")}
+	if configDir, err := ConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(configDir, "templates", strings.ToLower(language)))
+	}
+	return dirs
+}
+
+// renderTemplate renders the first override of name found in templateDirs,
+// falling back to builtin. It returns the rendered text and the path of the
+// override used, or "" for the built-in template.
+func renderTemplate(projectPath, language, name, builtin string, data TemplateData) (string, string, error) {
+	text, source := builtin, ""
+	for _, dir := range templateDirs(projectPath, language) {
+		path := filepath.Join(dir, name)
+		content, err := os.ReadFile(path)
+		if err == nil {
+			text, source = string(content), path
+			break
+		}
+		if !os.IsNotExist(err) {
+			return "", "", fmt.Errorf("failed to read template %s: %w", path, err)
+		}
+	}
+
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", "", templateError(source, err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", "", templateError(source, err)
+	}
+	return buf.String(), source, nil
+}
+
+func templateError(source string, err error) error {
+	if source == "" {
+		return err
+	}
+	return fmt.Errorf("template %s: %w", source, err)
+}
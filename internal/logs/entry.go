@@ -0,0 +1,144 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxLineSize is the longest single log line that will be read.
+const MaxLineSize = 16 * 1024 * 1024
+
+// timestampLayouts are the timestamp formats logdog loggers have written.
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05",
+	time.RFC3339Nano,
+	time.RFC3339,
+}
+
+// Entry is one line of a logdog NDJSON file.
+type Entry struct {
+	Raw       string
+	Timestamp string
+	// Time is the parsed Timestamp, or the zero time if it couldn't be parsed.
+	Time    time.Time
+	Level   string
+	Message string
+	Data    map[string]any
+	// Valid is false for lines that aren't JSON objects; only Raw is set.
+	Valid bool
+	// Source is the project the entry was read from, set when entries from
+	// several files are merged.
+	Source string
+}
+
+// Parse decodes a single log line.
+func Parse(line string) Entry {
+	entry := Entry{Raw: line}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return entry
+	}
+
+	entry.Valid = true
+	entry.Timestamp, _ = fields["timestamp"].(string)
+	entry.Level, _ = fields["level"].(string)
+	entry.Message, _ = fields["message"].(string)
+	entry.Data, _ = fields["data"].(map[string]any)
+	entry.Time = ParseTime(entry.Timestamp)
+	return entry
+}
+
+// ParseTime parses a logdog timestamp in local time, returning the zero
+// time if it isn't in a known format.
+func ParseTime(timestamp string) time.Time {
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, timestamp, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Scan calls fn for every non-blank line read from r. Returning an error
+// from fn stops the scan and returns that error.
+func Scan(r io.Reader, fn func(Entry) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxLineSize)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := fn(Parse(line)); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// ReadFile reads every entry of a log file.
+func ReadFile(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	err = Scan(file, func(e Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return entries, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// DataValue looks up key in the entry's data. Dotted keys such as
+// "user.id" reach into nested objects when there is no literal key of that
+// name.
+func (e Entry) DataValue(key string) (any, bool) {
+	if v, ok := e.Data[key]; ok {
+		return v, true
+	}
+
+	var current any = e.Data
+	for _, part := range strings.Split(key, ".") {
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// FormatValue renders a data value as plain text, the way it is compared
+// by filters. Numbers are written out in full rather than with an exponent,
+// and nested objects and arrays are rendered as compact JSON.
+func FormatValue(v any) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]any, []any:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(v); err == nil {
+			return strings.TrimSuffix(buf.String(), "\n")
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
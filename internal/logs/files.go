@@ -0,0 +1,73 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
+)
+
+// dailyFileLayout is the date format in log file names.
+const dailyFileLayout = "01-02-2006"
+
+var dailyFilePattern = regexp.MustCompile(`^(.+)-logdog-(\d{2}-\d{2}-\d{4})\.json$`)
+
+// DailyFileName is the name of a project's log file for the given day:
+// <project>-logdog-MM-DD-YYYY.json.
+func DailyFileName(project string, day time.Time) string {
+	return fmt.Sprintf("%s-logdog-%s.json", project, day.Format(dailyFileLayout))
+}
+
+// ParseDailyFileName splits a log file name into its project and day.
+func ParseDailyFileName(name string) (project string, day time.Time, ok bool) {
+	match := dailyFilePattern.FindStringSubmatch(name)
+	if match == nil {
+		return "", time.Time{}, false
+	}
+	day, err := time.ParseInLocation(dailyFileLayout, match[2], time.Local)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return match[1], day, true
+}
+
+// ReadFrom reads the complete lines of a log file starting at byte offset,
+// returning their entries and the offset just past the last complete line.
+// A trailing line without a newline only counts as complete if it parses as
+// JSON; otherwise it is probably still being written and is left for the
+// next read.
+func ReadFrom(path string, offset int64) ([]Entry, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, offset, err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+
+	var entries []Entry
+	reader := bufio.NewReaderSize(file, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if entry := Parse(string(bytes.TrimSpace(line))); entry.Valid {
+				entries = append(entries, entry)
+				offset += int64(len(line))
+			}
+			return entries, offset, nil
+		}
+		if err != nil {
+			return entries, offset, err
+		}
+		offset += int64(len(line))
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			entries = append(entries, Parse(string(trimmed)))
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package logs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Source is one project's log directory in a timeline.
+type Source struct {
+	Name string
+	Dir  string
+}
+
+// Timeline merges the daily log files of one or more projects into a single
+// chronological stream. Files are only read a day at a time, when that day
+// is asked for.
+type Timeline struct {
+	sources []Source
+	// days holds every day with at least one file, oldest first; files maps
+	// each day to the file of every source that has one.
+	days  []time.Time
+	files map[time.Time][]dayFile
+}
+
+type dayFile struct {
+	source string
+	path   string
+}
+
+// NewTimeline indexes the daily log files in each source's directory. Files
+// whose names don't carry a date are left out.
+func NewTimeline(sources ...Source) (*Timeline, error) {
+	t := &Timeline{sources: sources, files: map[time.Time][]dayFile{}}
+	for _, source := range sources {
+		matches, err := filepath.Glob(filepath.Join(source.Dir, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matches {
+			_, day, ok := ParseDailyFileName(filepath.Base(path))
+			if !ok {
+				continue
+			}
+			if _, seen := t.files[day]; !seen {
+				t.days = append(t.days, day)
+			}
+			t.files[day] = append(t.files[day], dayFile{source: source.Name, path: path})
+		}
+	}
+	sort.Slice(t.days, func(i, j int) bool { return t.days[i].Before(t.days[j]) })
+	return t, nil
+}
+
+// Sources returns the sources the timeline was built from.
+func (t *Timeline) Sources() []Source {
+	return t.sources
+}
+
+// Days returns the days that have log files, oldest first.
+func (t *Timeline) Days() []time.Time {
+	return t.days
+}
+
+// DayIndex returns the index of the last day at or before at, or 0 if at is
+// before the first day.
+func (t *Timeline) DayIndex(at time.Time) int {
+	i := sort.Search(len(t.days), func(i int) bool { return t.days[i].After(at) })
+	return max(0, i-1)
+}
+
+// ReadDay reads the files for t.Days()[i] and merges their entries by time.
+// Each entry's Source is set to the name of the source it came from.
+func (t *Timeline) ReadDay(i int) ([]Entry, error) {
+	if i < 0 || i >= len(t.days) {
+		return nil, fmt.Errorf("no logs for day %d", i)
+	}
+
+	var streams [][]Entry
+	for _, file := range t.files[t.days[i]] {
+		entries, err := ReadFile(file.path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for j := range entries {
+			entries[j].Source = file.source
+		}
+		streams = append(streams, entries)
+	}
+	return Merge(streams...), nil
+}
+
+// Merge interleaves streams that are each in time order into one, keeping
+// the order within each stream. Entries without a timestamp stay right
+// after the entry before them in their stream.
+func Merge(streams ...[]Entry) []Entry {
+	if len(streams) == 1 {
+		return streams[0]
+	}
+
+	total := 0
+	for _, s := range streams {
+		total += len(s)
+	}
+	merged := make([]Entry, 0, total)
+
+	pos := make([]int, len(streams))
+	last := make([]time.Time, len(streams))
+	for len(merged) < total {
+		best := -1
+		var bestTime time.Time
+		for i, s := range streams {
+			if pos[i] >= len(s) {
+				continue
+			}
+			at := s[pos[i]].Time
+			if at.IsZero() {
+				at = last[i]
+			}
+			if best < 0 || at.Before(bestTime) {
+				best, bestTime = i, at
+			}
+		}
+		entry := streams[best][pos[best]]
+		if !entry.Time.IsZero() {
+			last[best] = entry.Time
+		}
+		merged = append(merged, entry)
+		pos[best]++
+	}
+	return merged
+}
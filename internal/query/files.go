@@ -0,0 +1,90 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+// ErrStop can be returned from a Run callback to stop reading early. Run
+// then returns nil.
+var ErrStop = errors.New("stop query")
+
+// Files lists the log files in dir oldest first, leaving out daily files
+// that fall entirely outside the query's time range.
+func (q *Query) Files(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	type dated struct {
+		path string
+		day  time.Time
+	}
+	var files []dated
+	for _, path := range matches {
+		_, day, ok := logs.ParseDailyFileName(filepath.Base(path))
+		if ok {
+			if !q.Until.IsZero() && !day.Before(q.Until) {
+				continue
+			}
+			if !q.Since.IsZero() && !day.AddDate(0, 0, 1).After(q.Since) {
+				continue
+			}
+		}
+		files = append(files, dated{path, day})
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if !files[i].day.Equal(files[j].day) {
+			return files[i].day.Before(files[j].day)
+		}
+		return files[i].path < files[j].path
+	})
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+// Run reads files in order and calls fn with every entry matching the
+// query, along with the file it came from.
+func (q *Query) Run(files []string, fn func(file string, entry logs.Entry) error) error {
+	for _, path := range files {
+		err := q.runFile(path, fn)
+		if errors.Is(err, ErrStop) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *Query) runFile(path string, fn func(file string, entry logs.Entry) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	err = logs.Scan(file, func(entry logs.Entry) error {
+		if q.Match(entry) {
+			return fn(path, entry)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrStop) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return err
+}
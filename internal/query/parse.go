@@ -0,0 +1,308 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// token is a lexical element of a query. Quoted strings are never keywords
+// or operators, however they are spelled.
+type token struct {
+	text   string
+	quoted bool
+}
+
+func (t token) is(keyword string) bool {
+	return !t.quoted && strings.EqualFold(t.text, keyword)
+}
+
+func (t token) isOp() bool {
+	if t.quoted {
+		return false
+	}
+	switch t.text {
+	case "=", "!=", ">", ">=", "<", "<=", "~", "!~":
+		return true
+	}
+	return false
+}
+
+// operatorChars end a bare word.
+const operatorChars = "=!<>~()\""
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, token{text: string(r)})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string starting at %d", i+1)
+			}
+			text, err := strconv.Unquote(string(runes[i : end+1]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s", string(runes[i:end+1]))
+			}
+			tokens = append(tokens, token{text: text, quoted: true})
+			i = end + 1
+		case strings.ContainsRune("=!<>~", r):
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' && r != '=' && r != '~' {
+				op += "="
+			} else if r == '!' && i+1 < len(runes) && runes[i+1] == '~' {
+				op = "!~"
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected '!' at %d", i+1)
+			}
+			tokens = append(tokens, token{text: op})
+			i += len(op)
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(operatorChars, runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{text: string(runes[start:i])})
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos], true
+	}
+	return token{}, false
+}
+
+func (p *parser) next() (token, bool) {
+	t, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return t, ok
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || !t.is("or") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+}
+
+// parseAnd joins terms with "and"; terms written next to each other are
+// joined the same way.
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.is("or") || (!t.quoted && t.text == ")") {
+			return left, nil
+		}
+		if t.is("and") {
+			p.next()
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+
+	switch {
+	case t.is("not"):
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	case !t.quoted && t.text == "(":
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing, ok := p.next(); !ok || closing.quoted || closing.text != ")" {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		return inner, nil
+	case t.isOp() || (!t.quoted && t.text == ")") || t.is("and") || t.is("or"):
+		return nil, fmt.Errorf("unexpected %q", t.text)
+	}
+
+	op, ok := p.peek()
+	if t.quoted || !ok || !op.isOp() {
+		return newTextNode(t.text)
+	}
+	p.next()
+
+	value, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("missing value after %s%s", t.text, op.text)
+	}
+	return newCompareNode(t.text, op.text, value.text)
+}
+
+// compilePattern compiles a ~ pattern. Like viewer search, it is
+// case-insensitive unless it contains an uppercase letter.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if strings.ToLower(pattern) == pattern {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// extractRange removes "since X" and "until X" clauses from the tokens,
+// along with an "and" joining them to the rest of the query.
+func extractRange(tokens []token, now time.Time) ([]token, time.Time, time.Time, error) {
+	var since, until time.Time
+	var rest []token
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if !t.is("since") && !t.is("until") {
+			rest = append(rest, t)
+			continue
+		}
+		if i+1 >= len(tokens) {
+			return nil, since, until, fmt.Errorf("missing time after %s", strings.ToLower(t.text))
+		}
+		value := tokens[i+1].text
+		i++
+
+		var err error
+		if t.is("since") {
+			since, err = parseTime(value, now, false)
+		} else {
+			until, err = parseTime(value, now, true)
+		}
+		if err != nil {
+			return nil, since, until, err
+		}
+
+		if len(rest) > 0 && rest[len(rest)-1].is("and") {
+			rest = rest[:len(rest)-1]
+		} else if i+1 < len(tokens) && tokens[i+1].is("and") && len(rest) == 0 {
+			i++
+		}
+	}
+	return rest, since, until, nil
+}
+
+// dateLayouts are the absolute times since and until accept, besides
+// durations ago and "today"/"yesterday".
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+}
+
+var agoPattern = regexp.MustCompile(`^(\d+[wdhms])+$`)
+var agoPart = regexp.MustCompile(`(\d+)([wdhms])`)
+
+// ParseTime parses a point in time written the way "since" takes it, e.g.
+// 2h (ago), yesterday, 2006-01-02 or "2006-01-02 15:04".
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	return parseTime(value, now, false)
+}
+
+// parseTime parses the argument of since/until: a duration ago such as 2h
+// or 7d, a date or date and time, a time of day today, "today" or
+// "yesterday". A bare date used as an end (until) includes the whole day.
+func parseTime(value string, now time.Time, end bool) (time.Time, error) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	dayEnd := func(t time.Time) time.Time {
+		if end {
+			return t.AddDate(0, 0, 1)
+		}
+		return t
+	}
+
+	switch strings.ToLower(value) {
+	case "today":
+		return dayEnd(midnight), nil
+	case "yesterday":
+		return dayEnd(midnight.AddDate(0, 0, -1)), nil
+	case "now":
+		return now, nil
+	}
+
+	if agoPattern.MatchString(value) {
+		var ago time.Duration
+		for _, part := range agoPart.FindAllStringSubmatch(value, -1) {
+			n, _ := strconv.Atoi(part[1])
+			unit := map[string]time.Duration{
+				"w": 7 * 24 * time.Hour,
+				"d": 24 * time.Hour,
+				"h": time.Hour,
+				"m": time.Minute,
+				"s": time.Second,
+			}[part[2]]
+			ago += time.Duration(n) * unit
+		}
+		return now.Add(-ago), nil
+	}
+
+	for i, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			if i == 0 {
+				return dayEnd(t), nil
+			}
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("15:04", value, time.Local); err == nil {
+		return midnight.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q, expected e.g. 2h, 7d, today, 2006-01-02 or \"2006-01-02 15:04\"", value)
+}
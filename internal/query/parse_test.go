@@ -0,0 +1,137 @@
+package query
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+var testNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+
+func TestParseMatch(t *testing.T) {
+	lines := map[string]string{
+		"error":   `{"timestamp": "2026-10-18 11:00:00", "level": "ERROR", "message": "payment failed", "data": {"user_id": 1234567, "path": "/pay", "duration_ms": 250}}`,
+		"warn":    `{"timestamp": "2026-10-18 09:00:00", "level": "WARN", "message": "slow request", "data": {"path": "/cart", "duration_ms": 1200.0, "req": {"id": "abc"}}}`,
+		"info":    `{"timestamp": "2026-10-17 23:00:00", "level": "INFO", "message": "user logged in", "data": {"user_id": "42"}}`,
+		"invalid": `panic: runtime error at main.go:12`,
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"error", "warn", "info", "invalid"}},
+		{"payment", []string{"error"}},
+		{"PAYMENT", nil},
+		{"runtime", []string{"invalid"}},
+		{"level=error", []string{"error"}},
+		{"level>=WARN", []string{"error", "warn"}},
+		{"level<warn", []string{"info"}},
+		{"level!=INFO", []string{"error", "warn", "invalid"}},
+		{"user_id=1234567", []string{"error"}},
+		{"user_id=1234567.0", []string{"error"}},
+		{"user_id=42", []string{"info"}},
+		{"duration_ms>1000", []string{"warn"}},
+		{"duration_ms<=250", []string{"error"}},
+		{"data.path=/pay", []string{"error"}},
+		{"req.id=abc", []string{"warn"}},
+		{"path!=/pay", []string{"warn", "info", "invalid"}},
+		{"message~^user", []string{"info"}},
+		{"msg!~slow", []string{"error", "info", "invalid"}},
+		{`"level=error"`, nil},
+		{"level=error or level=warn", []string{"error", "warn"}},
+		{"slow and request", []string{"warn"}},
+		{"slow request", []string{"warn"}},
+		{"not level=error", []string{"warn", "info", "invalid"}},
+		{"(payment or slow) and path=/cart", []string{"warn"}},
+		{"level>=warn since today", []string{"error", "warn"}},
+		{"since 2h", []string{"error"}},
+		{"until today", []string{"error", "warn", "info"}},
+		{"until yesterday", []string{"info"}},
+		{"since yesterday until 2026-10-17", []string{"info"}},
+		{`since "2026-10-18 09:00" and level=warn`, []string{"warn"}},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.query, testNow)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.query, err)
+			continue
+		}
+		var got []string
+		for _, name := range []string{"error", "warn", "info", "invalid"} {
+			if q.Match(logs.Parse(lines[name])) {
+				got = append(got, name)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Parse(%q) matched %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"level=",
+		"level=fatal",
+		"(level=error",
+		"level=error)",
+		"and level=error",
+		"level=error or",
+		"not",
+		`"unterminated`,
+		"a ! b",
+		"message~(",
+		"data.=1",
+		"since",
+		"since someday",
+		"= 5",
+	}
+
+	for _, query := range tests {
+		if _, err := Parse(query, testNow); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", query)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	midnight := time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		value string
+		end   bool
+		want  time.Time
+	}{
+		{"now", false, testNow},
+		{"2h", false, testNow.Add(-2 * time.Hour)},
+		{"1d12h", false, testNow.Add(-36 * time.Hour)},
+		{"1w", false, testNow.AddDate(0, 0, -7)},
+		{"today", false, midnight},
+		{"today", true, midnight.AddDate(0, 0, 1)},
+		{"yesterday", false, midnight.AddDate(0, 0, -1)},
+		{"2026-10-01", false, time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)},
+		{"2026-10-01", true, time.Date(2026, 10, 2, 0, 0, 0, 0, time.Local)},
+		{"2026-10-01 15:04", true, time.Date(2026, 10, 1, 15, 4, 0, 0, time.Local)},
+		{"2026-10-01T15:04:05", false, time.Date(2026, 10, 1, 15, 4, 5, 0, time.Local)},
+		{"09:30", false, midnight.Add(9*time.Hour + 30*time.Minute)},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTime(tt.value, testNow, tt.end)
+		if err != nil {
+			t.Errorf("parseTime(%q, %v): %v", tt.value, tt.end, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseTime(%q, %v) = %v, want %v", tt.value, tt.end, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "2x", "h", "2026-13-01", "tomorrow"} {
+		if _, err := parseTime(value, testNow, false); err == nil {
+			t.Errorf("parseTime(%q) succeeded, want an error", value)
+		}
+	}
+}
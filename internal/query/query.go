@@ -0,0 +1,253 @@
+// Package query implements logdog's query language for finding log entries
+// across files, e.g.
+//
+//	level>=WARN and data.user_id=123 and message~"timeout" since 2h
+//
+// Terms compare a field with =, !=, >, >=, <, <=, ~ (regex match) or !~.
+// Fields are level, message (or msg), and data keys, written either as
+// data.user_id or just user_id; dotted keys reach into nested objects. A
+// term without an operator matches entries whose message or data contains
+// it. Terms combine with and (or just a space), or, not and parentheses.
+// "since" and "until" limit the time range, and with it the files read.
+package query
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+// Query is a parsed query.
+type Query struct {
+	text string
+	expr node
+	// Since and Until bound the entries' timestamps; zero means unbounded.
+	// Until is exclusive.
+	Since time.Time
+	Until time.Time
+}
+
+// Parse parses a query. Relative times such as "since 2h" are relative to
+// now. An empty query matches everything.
+func Parse(input string, now time.Time) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{text: strings.TrimSpace(input)}
+	tokens, q.Since, q.Until, err = extractRange(tokens, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return q, nil
+	}
+
+	p := &parser{tokens: tokens}
+	if q.expr, err = p.parseOr(); err != nil {
+		return nil, err
+	}
+	if t, ok := p.peek(); ok {
+		return nil, errors.New("unexpected " + strconv.Quote(t.text))
+	}
+	return q, nil
+}
+
+func (q *Query) String() string {
+	return q.text
+}
+
+// Match reports whether an entry satisfies the query. Entries without a
+// usable timestamp never match a query with a time range.
+func (q *Query) Match(entry logs.Entry) bool {
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		if entry.Time.IsZero() {
+			return false
+		}
+		if !q.Since.IsZero() && entry.Time.Before(q.Since) {
+			return false
+		}
+		if !q.Until.IsZero() && !entry.Time.Before(q.Until) {
+			return false
+		}
+	}
+	return q.expr == nil || q.expr.match(entry)
+}
+
+// node is one part of a parsed query expression.
+type node interface {
+	match(entry logs.Entry) bool
+}
+
+type andNode struct{ left, right node }
+type orNode struct{ left, right node }
+type notNode struct{ inner node }
+
+func (n andNode) match(e logs.Entry) bool { return n.left.match(e) && n.right.match(e) }
+func (n orNode) match(e logs.Entry) bool  { return n.left.match(e) || n.right.match(e) }
+func (n notNode) match(e logs.Entry) bool { return !n.inner.match(e) }
+
+// textNode matches a bare word against the message and data values, or the
+// whole line for entries that aren't JSON.
+type textNode struct {
+	re *regexp.Regexp
+}
+
+func newTextNode(text string) (node, error) {
+	re, err := compilePattern(regexp.QuoteMeta(text))
+	return textNode{re}, err
+}
+
+func (n textNode) match(e logs.Entry) bool {
+	if !e.Valid {
+		return n.re.MatchString(e.Raw)
+	}
+	if n.re.MatchString(e.Message) {
+		return true
+	}
+	for _, v := range e.Data {
+		if n.re.MatchString(logs.FormatValue(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// levels orders the log levels for level comparisons.
+var levels = []string{"DEBUG", "INFO", "WARN", "ERROR"}
+
+func levelRank(level string) int {
+	for i, l := range levels {
+		if strings.EqualFold(l, level) {
+			return i
+		}
+	}
+	return -1
+}
+
+// compareNode is a field/operator/value term.
+type compareNode struct {
+	field string
+	op    string
+	value string
+	re    *regexp.Regexp
+	// number is value as a number, if it is one.
+	number   float64
+	isNumber bool
+}
+
+func newCompareNode(field, op, value string) (node, error) {
+	n := compareNode{field: strings.ToLower(field), op: op, value: value}
+	switch n.field {
+	case "level", "message", "msg":
+	default:
+		// Data keys keep their case.
+		n.field = strings.TrimPrefix(field, "data.")
+		if n.field == "" {
+			return nil, errors.New("missing data key in " + strconv.Quote(field))
+		}
+		n.field = "data." + n.field
+	}
+
+	if op == "~" || op == "!~" {
+		re, err := compilePattern(value)
+		if err != nil {
+			return nil, err
+		}
+		n.re = re
+		return n, nil
+	}
+
+	if n.field == "level" && levelRank(value) < 0 {
+		return nil, errors.New("unknown level " + strconv.Quote(value) + ", expected DEBUG, INFO, WARN or ERROR")
+	}
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		n.number, n.isNumber = number, true
+	}
+	return n, nil
+}
+
+func (n compareNode) match(e logs.Entry) bool {
+	var actual string
+	var present bool
+	var number float64
+	var isNumber bool
+
+	switch n.field {
+	case "level":
+		if !e.Valid || e.Level == "" {
+			return n.op == "!=" || n.op == "!~"
+		}
+		if n.re == nil {
+			return compareOrdered(levelRank(e.Level)-levelRank(n.value), n.op)
+		}
+		actual, present = e.Level, true
+	case "message", "msg":
+		actual, present = e.Message, e.Valid
+	default:
+		v, ok := e.DataValue(strings.TrimPrefix(n.field, "data."))
+		if ok {
+			actual, present = logs.FormatValue(v), true
+			number, isNumber = v.(float64)
+		}
+	}
+
+	switch n.op {
+	case "~":
+		return present && n.re.MatchString(actual)
+	case "!~":
+		return !present || !n.re.MatchString(actual)
+	case "!=":
+		return !present || !equal(actual, number, isNumber, n)
+	case "=":
+		return present && equal(actual, number, isNumber, n)
+	}
+
+	if !present {
+		return false
+	}
+	if isNumber && n.isNumber {
+		switch {
+		case number < n.number:
+			return compareOrdered(-1, n.op)
+		case number > n.number:
+			return compareOrdered(1, n.op)
+		}
+		return compareOrdered(0, n.op)
+	}
+	return compareOrdered(strings.Compare(actual, n.value), n.op)
+}
+
+// equal compares a field's value with the query's, numerically when both
+// are numbers so that 123 matches 123.0.
+func equal(actual string, number float64, isNumber bool, n compareNode) bool {
+	if isNumber && n.isNumber {
+		return number == n.number
+	}
+	return actual == n.value
+}
+
+// compareOrdered applies an ordering operator to the result of a
+// comparison (negative, zero or positive).
+func compareOrdered(cmp int, op string) bool {
+	switch op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+const (
+	// AnomalyWindow is how far back from now anomalies are reported.
+	AnomalyWindow = 24 * time.Hour
+	// BaselineDays is how much history before each hour its baseline is
+	// averaged over.
+	BaselineDays = 7
+	// AnomalyFactor is how many times its baseline an hour's count must be
+	// to stand out, and AnomalyMinCount the fewest entries that can.
+	AnomalyFactor   = 5
+	AnomalyMinCount = 3
+)
+
+// Anomaly is an hour in which an error group was logged far more often
+// than usual, or a group that had never been seen before.
+type Anomaly struct {
+	Group *Group
+	// Hour is the start of the hour the anomaly was in; for a new group,
+	// the hour it first appeared.
+	Hour  time.Time
+	Count int
+	// Baseline is the group's average count per hour over the BaselineDays
+	// before Hour.
+	Baseline float64
+	New      bool
+}
+
+// Anomalies compares the last AnomalyWindow before now against the history
+// in the report. An hour is anomalous when a group's count is at least
+// AnomalyFactor times its rolling baseline; a group is new when it first
+// appears in the window although there are logs from before it. Nothing is
+// reported without such history to compare against.
+func (r *Report) Anomalies(now time.Time) []Anomaly {
+	windowStart := now.Add(-AnomalyWindow).Truncate(time.Hour)
+	if r.First.IsZero() || !r.First.Before(windowStart) {
+		return nil
+	}
+	historyStart := r.First.Truncate(time.Hour)
+
+	var anomalies []Anomaly
+	for _, group := range r.Groups() {
+		hourly := map[time.Time]int{}
+		for _, t := range group.times {
+			hourly[t.Truncate(time.Hour)]++
+		}
+
+		if !group.First.Before(windowStart) {
+			count := 0
+			for hour, n := range hourly {
+				if !hour.Before(windowStart) {
+					count += n
+				}
+			}
+			anomalies = append(anomalies, Anomaly{Group: group, Hour: group.First.Truncate(time.Hour), Count: count, New: true})
+			continue
+		}
+
+		for hour, count := range hourly {
+			if hour.Before(windowStart) || count < AnomalyMinCount {
+				continue
+			}
+			from := hour.AddDate(0, 0, -BaselineDays)
+			if from.Before(historyStart) {
+				from = historyStart
+			}
+			hours := hour.Sub(from).Hours()
+			if hours < 1 {
+				continue
+			}
+			before := 0
+			for h, n := range hourly {
+				if !h.Before(from) && h.Before(hour) {
+					before += n
+				}
+			}
+			baseline := float64(before) / hours
+			if float64(count) >= AnomalyFactor*baseline {
+				anomalies = append(anomalies, Anomaly{Group: group, Hour: hour, Count: count, Baseline: baseline})
+			}
+		}
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		if anomalies[i].New != anomalies[j].New {
+			return anomalies[i].New
+		}
+		if !anomalies[i].Hour.Equal(anomalies[j].Hour) {
+			return anomalies[i].Hour.After(anomalies[j].Hour)
+		}
+		return anomalies[i].Group.Fingerprint < anomalies[j].Group.Fingerprint
+	})
+	return anomalies
+}
+
+// RecentFiles returns the log files in dir dated within the baseline and
+// anomaly window before now, enough to compute Anomalies.
+func RecentFiles(dir string, now time.Time) ([]string, error) {
+	return FilesBetween(dir, now.AddDate(0, 0, -BaselineDays-1), now)
+}
+
+// FilesBetween returns the daily log files in dir dated from the day of
+// from through the day of to.
+func FilesBetween(dir string, from, to time.Time) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	first := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	last := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local)
+
+	var files []string
+	for _, path := range matches {
+		_, day, ok := logs.ParseDailyFileName(filepath.Base(path))
+		if ok && !day.Before(first) && !day.After(last) {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// ScanAnomalies reads a project's recent log files and returns their
+// anomalies.
+func ScanAnomalies(dir string, now time.Time) ([]Anomaly, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	files, err := RecentFiles(dir, now)
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+	report, err := Collect(files, nil)
+	if err != nil {
+		return nil, err
+	}
+	return report.Anomalies(now), nil
+}
@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"os"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+// Changes is what a project logged after a point in time, such as the
+// user's last visit.
+type Changes struct {
+	Since    time.Time
+	Errors   int
+	Warnings int
+	// NewGroups are the error and warning groups first seen after Since.
+	NewGroups []*Group
+	// LatestError is the most recent ERROR entry after Since, if any.
+	LatestError *logs.Entry
+}
+
+// Empty reports whether nothing worth looking at was logged.
+func (c Changes) Empty() bool {
+	return c.Errors == 0 && c.Warnings == 0
+}
+
+// ChangesSince counts the errors and warnings in the report logged after
+// since. A group is new when the report holds none of its entries from
+// before since, so it should cover some history before it.
+func (r *Report) ChangesSince(since time.Time) Changes {
+	changes := Changes{Since: since}
+	for _, group := range r.Groups() {
+		after := 0
+		for _, t := range group.times {
+			if t.After(since) {
+				after++
+			}
+		}
+		switch group.Level {
+		case "ERROR":
+			changes.Errors += after
+			latest := &group.Samples[0]
+			if after > 0 && (changes.LatestError == nil || latest.Time.After(changes.LatestError.Time)) {
+				changes.LatestError = latest
+			}
+		case "WARN":
+			changes.Warnings += after
+		}
+		if group.First.After(since) {
+			changes.NewGroups = append(changes.NewGroups, group)
+		}
+	}
+	return changes
+}
+
+// ScanChanges reads a project's log files from BaselineDays before since up
+// to now and returns what changed after since.
+func ScanChanges(dir string, since, now time.Time) (Changes, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return Changes{Since: since}, err
+	}
+	files, err := FilesBetween(dir, since.AddDate(0, 0, -BaselineDays), now)
+	if err != nil || len(files) == 0 {
+		return Changes{Since: since}, err
+	}
+	report, err := Collect(files, nil)
+	if err != nil {
+		return Changes{Since: since}, err
+	}
+	return report.ChangesSince(since), nil
+}
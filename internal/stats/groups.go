@@ -0,0 +1,158 @@
+package stats
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+// Group is a cluster of ERROR or WARN entries that differ only in the
+// variable parts of their message, such as ids, numbers and paths.
+type Group struct {
+	Fingerprint string
+	Level       string
+	// Pattern is the normalized message, e.g. `user <n> not found in <path>`.
+	Pattern string
+	// Caller and Error are the entries' caller field and normalized error
+	// field, when they have them.
+	Caller string
+	Error  string
+	Count  int
+	First  time.Time
+	Last   time.Time
+	// Samples are up to GroupSamples of the group's entries, most recent
+	// first; entries without a time come last. The rest are only counted,
+	// so a large log doesn't have to be held in memory.
+	Samples []logs.Entry
+	// times are when each entry was logged, for counting them by hour or
+	// since a point in time.
+	times []time.Time
+}
+
+// GroupSamples is how many entries a group keeps.
+const GroupSamples = 3
+
+// addSample keeps entry if it is among the GroupSamples most recent.
+func (g *Group) addSample(entry logs.Entry) {
+	i := sort.Search(len(g.Samples), func(i int) bool {
+		t := g.Samples[i].Time
+		return t.IsZero() || (!entry.Time.IsZero() && !entry.Time.Before(t))
+	})
+	if i >= GroupSamples {
+		return
+	}
+	g.Samples = slices.Insert(g.Samples, i, entry)
+	if len(g.Samples) > GroupSamples {
+		g.Samples = g.Samples[:GroupSamples]
+	}
+}
+
+// groupedLevels are the levels whose entries are grouped.
+var groupedLevels = map[string]bool{"ERROR": true, "WARN": true}
+
+// The patterns Normalize replaces, in order: earlier ones would otherwise
+// be broken up by later ones, e.g. the digits of a UUID.
+var normalizers = []struct {
+	re          *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`"[^"]*"|\B'[^']*'\B|` + "`[^`]*`"), "<str>"},
+	{regexp.MustCompile(`\b[a-z][a-z0-9+.-]*://\S+`), "<url>"},
+	{regexp.MustCompile(`(?:[A-Za-z]:)?(?:\.{0,2}/[\w.@%+-]+){2,}/?|(?:[A-Za-z]:)?(?:\\[\w.@%+-]+){2,}`), "<path>"},
+	{regexp.MustCompile(`(?i)\b(?:0x[0-9a-f]+|[0-9a-f]{8,})\b`), "<hex>"},
+	{regexp.MustCompile(`\d+(?:\.\d+)?`), "<n>"},
+}
+
+// Normalize replaces the parts of a message that vary between occurrences
+// of the same error with placeholders: UUIDs, quoted values, URLs, paths,
+// hex ids and numbers.
+func Normalize(message string) string {
+	for _, n := range normalizers {
+		message = n.re.ReplaceAllStringFunc(message, func(match string) string {
+			// Long runs of digits are left for the number pattern.
+			if n.placeholder == "<hex>" && strings.Trim(match, "0123456789") == "" {
+				return match
+			}
+			return n.placeholder
+		})
+	}
+	return strings.Join(strings.Fields(message), " ")
+}
+
+// Fingerprint identifies the group an entry belongs to: its level, its
+// normalized message and, when present, its caller and normalized error
+// field. ok is false for entries that aren't grouped.
+func Fingerprint(entry logs.Entry) (fingerprint string, ok bool) {
+	if !entry.Valid || !groupedLevels[entry.Level] {
+		return "", false
+	}
+	caller, errText := groupFields(entry)
+	return strings.Join([]string{entry.Level, Normalize(entry.Message), caller, errText}, "\x00"), true
+}
+
+// groupFields returns the caller and normalized error fields of an entry.
+func groupFields(entry logs.Entry) (caller, errText string) {
+	if v, ok := entry.Data["caller"]; ok {
+		caller = logs.FormatValue(v)
+	}
+	if v, ok := entry.Data["error"]; ok {
+		errText = Normalize(logs.FormatValue(v))
+	}
+	return caller, errText
+}
+
+func (r *Report) group(entry logs.Entry) {
+	fingerprint, ok := Fingerprint(entry)
+	if !ok {
+		return
+	}
+
+	group := r.groups[fingerprint]
+	if group == nil {
+		caller, errText := groupFields(entry)
+		group = &Group{
+			Fingerprint: fingerprint,
+			Level:       entry.Level,
+			Pattern:     Normalize(entry.Message),
+			Caller:      caller,
+			Error:       errText,
+		}
+		r.groups[fingerprint] = group
+	}
+
+	group.Count++
+	group.addSample(entry)
+	if !entry.Time.IsZero() {
+		group.times = append(group.times, entry.Time)
+		if group.First.IsZero() || entry.Time.Before(group.First) {
+			group.First = entry.Time
+		}
+		if entry.Time.After(group.Last) {
+			group.Last = entry.Time
+		}
+	}
+}
+
+// Groups returns the ERROR and WARN groups, largest first; groups of the
+// same size are ordered by most recently seen.
+func (r *Report) Groups() []*Group {
+	groups := make([]*Group, 0, len(r.groups))
+	for _, group := range r.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		if !groups[i].Last.Equal(groups[j].Last) {
+			return groups[i].Last.After(groups[j].Last)
+		}
+		return groups[i].Fingerprint < groups[j].Fingerprint
+	})
+	return groups
+}
@@ -0,0 +1,109 @@
+package stats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"user 42 not found", "user <n> not found"},
+		{"took 30.5s", "took <n>s"},
+		{"failed to open /var/lib/app/data.db: permission denied", "failed to open <path>: permission denied"},
+		{`can't read C:\Users\bob\app.log`, "can't read <path>"},
+		{"request 3f2b8c1e-9a4d-4e2f-b1c3-0A9E8D7C6B5A timed out", "request <uuid> timed out"},
+		{`invalid value "abc 12" for field 'name'`, "invalid value <str> for field <str>"},
+		{"can't open 'x' for user's 'name'", "can't open <str> for user's <str>"},
+		{"GET https://api.example.com/users/12?page=3 returned 502", "GET <url> returned <n>"},
+		{"commit deadbeef1234 at 0x7ffe", "commit <hex> at <hex>"},
+		{"order 123456789 failed", "order <n> failed"},
+		{"connecting to db-2 on port 5432", "connecting to db-<n> on port <n>"},
+		{"  panic:\n\tsomething   broke ", "panic: something broke"},
+		{"no variable parts", "no variable parts"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.message); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	fingerprint := func(line string) string {
+		f, ok := Fingerprint(logs.Parse(line))
+		if !ok {
+			return ""
+		}
+		return f
+	}
+
+	a := fingerprint(`{"level": "ERROR", "message": "user 42 not found", "data": {"caller": "api.go:10"}}`)
+	if a == "" {
+		t.Fatal("ERROR entry isn't grouped")
+	}
+	if b := fingerprint(`{"level": "ERROR", "message": "user 7 not found", "data": {"caller": "api.go:10"}}`); b != a {
+		t.Errorf("messages differing only in a number are in different groups")
+	}
+	if b := fingerprint(`{"level": "ERROR", "message": "user 7 not found", "data": {"caller": "db.go:99"}}`); b == a {
+		t.Errorf("entries from different callers are in the same group")
+	}
+	if b := fingerprint(`{"level": "WARN", "message": "user 42 not found", "data": {"caller": "api.go:10"}}`); b == a {
+		t.Errorf("ERROR and WARN entries are in the same group")
+	}
+	for _, line := range []string{
+		`{"level": "INFO", "message": "user 42 not found"}`,
+		`user 42 not found`,
+	} {
+		if f := fingerprint(line); f != "" {
+			t.Errorf("%s is grouped, want it left out", line)
+		}
+	}
+}
+
+func TestGroupSamples(t *testing.T) {
+	base := time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+	r := newReport()
+	// Read out of order, with one entry that has no time.
+	for _, minute := range []int{5, 1, -1, 9, 3, 7} {
+		timestamp := ""
+		if minute >= 0 {
+			timestamp = base.Add(time.Duration(minute) * time.Minute).Format("2006-01-02 15:04:05")
+		}
+		r.Add(logs.Parse(fmt.Sprintf(`{"timestamp": %q, "level": "ERROR", "message": "job %d failed"}`, timestamp, 10+minute)))
+	}
+	r.Add(logs.Parse(`{"level": "WARN", "message": "disk at 91%"}`))
+
+	groups := r.Groups()
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(groups))
+	}
+	group := groups[0]
+	if group.Count != 6 || group.Pattern != "job <n> failed" {
+		t.Errorf("first group is %q with %d entries, want %q with 6", group.Pattern, group.Count, "job <n> failed")
+	}
+	if !group.First.Equal(base.Add(time.Minute)) || !group.Last.Equal(base.Add(9*time.Minute)) {
+		t.Errorf("seen from %v to %v, want minutes 1 to 9", group.First, group.Last)
+	}
+
+	var messages []string
+	for _, entry := range group.Samples {
+		messages = append(messages, entry.Message)
+	}
+	if got, want := fmt.Sprint(messages), "[job 19 failed job 17 failed job 15 failed]"; got != want {
+		t.Errorf("samples %s, want %s", got, want)
+	}
+	if undated := groups[1].Samples; len(undated) != 1 || undated[0].Message != "disk at 91%" {
+		t.Errorf("undated group samples %v, want its one entry", undated)
+	}
+
+	changes := r.ChangesSince(base.Add(4 * time.Minute))
+	if changes.Errors != 3 || changes.LatestError == nil || changes.LatestError.Message != "job 19 failed" {
+		t.Errorf("ChangesSince() = %d errors, latest %v, want 3, job 19 failed", changes.Errors, changes.LatestError)
+	}
+}
@@ -0,0 +1,225 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+// Distribution is the spread of the values of a numeric field.
+type Distribution struct {
+	// Name is the field, or for a group the value of the field grouped by.
+	Name   string
+	values []float64
+	sum    float64
+	sorted bool
+}
+
+// Bucket is one bar of a histogram, counting values in [Low, High).
+type Bucket struct {
+	Low   float64
+	High  float64
+	Count int
+}
+
+func (d *Distribution) Add(v float64) {
+	d.values = append(d.values, v)
+	d.sum += v
+	d.sorted = false
+}
+
+func (d *Distribution) sort() {
+	if !d.sorted {
+		sort.Float64s(d.values)
+		d.sorted = true
+	}
+}
+
+func (d *Distribution) Count() int {
+	return len(d.values)
+}
+
+func (d *Distribution) Min() float64 {
+	if len(d.values) == 0 {
+		return 0
+	}
+	d.sort()
+	return d.values[0]
+}
+
+func (d *Distribution) Max() float64 {
+	if len(d.values) == 0 {
+		return 0
+	}
+	d.sort()
+	return d.values[len(d.values)-1]
+}
+
+func (d *Distribution) Mean() float64 {
+	if len(d.values) == 0 {
+		return 0
+	}
+	return d.sum / float64(len(d.values))
+}
+
+// Percentile returns the p-th percentile (0-100), interpolating between the
+// two nearest values.
+func (d *Distribution) Percentile(p float64) float64 {
+	if len(d.values) == 0 {
+		return 0
+	}
+	d.sort()
+	rank := p / 100 * float64(len(d.values)-1)
+	low := int(math.Floor(rank))
+	high := min(low+1, len(d.values)-1)
+	return d.values[low] + (d.values[high]-d.values[low])*(rank-float64(low))
+}
+
+// Histogram splits the range from the smallest to the largest value into n
+// equal buckets; the last one includes the largest value.
+func (d *Distribution) Histogram(n int) []Bucket {
+	if len(d.values) == 0 || n < 1 {
+		return nil
+	}
+	low, high := d.Min(), d.Max()
+	if low == high {
+		return []Bucket{{Low: low, High: high, Count: len(d.values)}}
+	}
+
+	width := (high - low) / float64(n)
+	buckets := make([]Bucket, n)
+	for i := range buckets {
+		buckets[i].Low = low + float64(i)*width
+		buckets[i].High = low + float64(i+1)*width
+	}
+	for _, v := range d.values {
+		i := max(0, min(n-1, int((v-low)/width)))
+		buckets[i].Count++
+	}
+	return buckets
+}
+
+// NumericFields returns the distribution of every data key that has held
+// numbers, as read by NumberValue, sorted by key.
+func (r *Report) NumericFields() []*Distribution {
+	fields := make([]*Distribution, 0, len(r.numbers))
+	for _, d := range r.numbers {
+		fields = append(fields, d)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields
+}
+
+// Numbers is the distribution of one numeric field, overall and, when By is
+// set, for each value of another field.
+type Numbers struct {
+	Field string
+	By    string
+	All   *Distribution
+	// Groups is keyed by the value of By, a data key or, as in queries,
+	// level or message; entries without it are grouped under "(none)".
+	Groups map[string]*Distribution
+}
+
+// NoGroup is the group of entries that lack the field grouped by.
+const NoGroup = "(none)"
+
+func NewNumbers(field, by string) *Numbers {
+	return &Numbers{
+		Field:  field,
+		By:     by,
+		All:    &Distribution{Name: field},
+		Groups: map[string]*Distribution{},
+	}
+}
+
+// ParseNumbers parses a spec of the form "field" or "field by key".
+func ParseNumbers(spec string) (*Numbers, error) {
+	words := strings.Fields(spec)
+	switch {
+	case len(words) == 1:
+		return NewNumbers(words[0], ""), nil
+	case len(words) == 3 && strings.EqualFold(words[1], "by"):
+		return NewNumbers(words[0], words[2]), nil
+	}
+	return nil, fmt.Errorf("expected \"field\" or \"field by key\", got %q", spec)
+}
+
+// Add counts an entry's value of the field, if it has a numeric one.
+func (n *Numbers) Add(entry logs.Entry) {
+	raw, ok := entry.DataValue(n.Field)
+	if !ok {
+		return
+	}
+	v, ok := NumberValue(raw)
+	if !ok {
+		return
+	}
+	n.All.Add(v)
+	if n.By == "" {
+		return
+	}
+
+	group := NoGroup
+	if by, ok := entry.DataValue(n.By); ok {
+		group = logs.FormatValue(by)
+	} else if strings.EqualFold(n.By, "level") && entry.Level != "" {
+		group = entry.Level
+	} else if (strings.EqualFold(n.By, "message") || strings.EqualFold(n.By, "msg")) && entry.Message != "" {
+		group = entry.Message
+	}
+	d := n.Groups[group]
+	if d == nil {
+		d = &Distribution{Name: group}
+		n.Groups[group] = d
+	}
+	d.Add(v)
+}
+
+// ByCount returns the groups with the most values first.
+func (n *Numbers) ByCount() []*Distribution {
+	groups := make([]*Distribution, 0, len(n.Groups))
+	for _, d := range n.Groups {
+		groups = append(groups, d)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count() != groups[j].Count() {
+			return groups[i].Count() > groups[j].Count()
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// NumberValue returns a data value as a number: JSON numbers, and strings
+// holding one, since some loggers quote them. NaN and infinities are not
+// numbers anything can be computed from.
+func NumberValue(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, !math.IsNaN(v) && !math.IsInf(v, 0)
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil && !math.IsNaN(f) && !math.IsInf(f, 0)
+	}
+	return 0, false
+}
+
+// FormatNumber renders a number without trailing zeros, e.g. 12, 0.5 or
+// 1234.57. Values that round to zero print as 0, never -0.
+func FormatNumber(v float64) string {
+	var s string
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		s = strconv.FormatFloat(v, 'f', 0, 64)
+	} else {
+		s = strings.TrimRight(strings.TrimRight(strconv.FormatFloat(v, 'f', 2, 64), "0"), ".")
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
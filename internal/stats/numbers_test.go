@@ -0,0 +1,154 @@
+package stats
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+func distribution(values ...float64) *Distribution {
+	d := &Distribution{Name: "test"}
+	for _, v := range values {
+		d.Add(v)
+	}
+	return d
+}
+
+func TestPercentile(t *testing.T) {
+	// Added out of order so Percentile has to sort them.
+	d := distribution(10, 1, 9, 2, 8, 3, 7, 4, 6, 5)
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0, 1},
+		{50, 5.5},
+		{95, 9.55},
+		{99, 9.91},
+		{100, 10},
+	}
+	for _, tt := range tests {
+		if got := d.Percentile(tt.p); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+
+	if got := d.Mean(); got != 5.5 {
+		t.Errorf("Mean() = %v, want 5.5", got)
+	}
+	if d.Min() != 1 || d.Max() != 10 {
+		t.Errorf("Min(), Max() = %v, %v, want 1, 10", d.Min(), d.Max())
+	}
+	if got := distribution(42).Percentile(99); got != 42 {
+		t.Errorf("Percentile(99) of one value = %v, want 42", got)
+	}
+	if got := distribution().Percentile(50); got != 0 {
+		t.Errorf("Percentile(50) of no values = %v, want 0", got)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	counts := func(buckets []Bucket) []int {
+		var n []int
+		for _, b := range buckets {
+			n = append(n, b.Count)
+		}
+		return n
+	}
+
+	tests := []struct {
+		name   string
+		values []float64
+		n      int
+		want   []int
+	}{
+		{"largest value in the last bucket", []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 5, []int{2, 2, 2, 2, 3}},
+		{"negative values", []float64{-5, -1, 0, 5}, 2, []int{2, 2}},
+		{"one distinct value", []float64{3, 3, 3}, 10, []int{3}},
+		{"no values", nil, 10, nil},
+		{"no buckets", []float64{1, 2}, 0, nil},
+	}
+	for _, tt := range tests {
+		got := distribution(tt.values...).Histogram(tt.n)
+		if !slices.Equal(counts(got), tt.want) {
+			t.Errorf("%s: counts %v, want %v", tt.name, counts(got), tt.want)
+		}
+	}
+
+	buckets := distribution(0, 10).Histogram(4)
+	if buckets[0].Low != 0 || buckets[1].Low != 2.5 || buckets[3].High != 10 {
+		t.Errorf("bucket bounds %v, want steps of 2.5 from 0 to 10", buckets)
+	}
+}
+
+func TestNumberValue(t *testing.T) {
+	tests := []struct {
+		value any
+		want  float64
+		ok    bool
+	}{
+		{float64(3), 3, true},
+		{-0.5, -0.5, true},
+		{" 42 ", 42, true},
+		{"1e3", 1000, true},
+		{"slow", 0, false},
+		{"NaN", 0, false},
+		{"-Inf", 0, false},
+		{math.NaN(), 0, false},
+		{math.Inf(1), 0, false},
+		{true, 0, false},
+		{nil, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := NumberValue(tt.value)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("NumberValue(%#v) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{12, "12"},
+		{0.5, "0.5"},
+		{1234.567, "1234.57"},
+		{-1.5, "-1.5"},
+		{2.999, "3"},
+		{0, "0"},
+		{math.Copysign(0, -1), "0"},
+		{-0.001, "0"},
+		{1e20, "100000000000000000000"},
+	}
+	for _, tt := range tests {
+		if got := FormatNumber(tt.value); got != tt.want {
+			t.Errorf("FormatNumber(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNumericFields(t *testing.T) {
+	r := newReport()
+	for _, line := range []string{
+		`{"level": "INFO", "message": "a", "data": {"ms": 5, "ok": true}}`,
+		`{"level": "INFO", "message": "b", "data": {"ms": "7", "req": {"bytes": 100}}}`,
+		`{"level": "INFO", "message": "c", "data": {"ms": "slow", "user": "bob"}}`,
+	} {
+		r.Add(logs.Parse(line))
+	}
+
+	var names []string
+	for _, d := range r.NumericFields() {
+		names = append(names, d.Name)
+	}
+	if want := []string{"ms", "req.bytes"}; !slices.Equal(names, want) {
+		t.Fatalf("NumericFields() = %v, want %v", names, want)
+	}
+	if ms := r.NumericFields()[0]; ms.Count() != 2 || ms.Mean() != 6 {
+		t.Errorf("ms has %d values with mean %v, want 2 with mean 6", ms.Count(), ms.Mean())
+	}
+}
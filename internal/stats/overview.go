@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+// tailSize is how much of the end of a file is read to find its last entry.
+const tailSize = 64 * 1024
+
+// Overview sums up a project's log directory at a glance.
+type Overview struct {
+	// Files counts the .json log files; Size is every file in the directory.
+	Files int
+	Size  int64
+	// FirstDay and LastDay are the dates of the oldest and newest daily
+	// files, zero if there are none.
+	FirstDay time.Time
+	LastDay  time.Time
+	// LastEntry is the time of the last entry in the newest daily file.
+	LastEntry   time.Time
+	ErrorsToday int
+}
+
+// ScanOverview walks a project's log directory. Only the end of the newest
+// daily file and all of today's are read.
+func ScanOverview(dir string, now time.Time) (Overview, error) {
+	var overview Overview
+	var newest string
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	var todays []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		overview.Size += info.Size()
+		if filepath.Ext(path) != ".json" {
+			return nil
+		}
+		overview.Files++
+
+		_, day, ok := logs.ParseDailyFileName(info.Name())
+		if !ok {
+			return nil
+		}
+		if overview.FirstDay.IsZero() || day.Before(overview.FirstDay) {
+			overview.FirstDay = day
+		}
+		if !day.Before(overview.LastDay) {
+			overview.LastDay = day
+			newest = path
+		}
+		if day.Equal(today) {
+			todays = append(todays, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return overview, err
+	}
+
+	if newest != "" {
+		if overview.LastEntry, err = lastEntryTime(newest); err != nil {
+			return overview, err
+		}
+	}
+	for _, path := range todays {
+		n, err := countLevel(path, "ERROR")
+		if err != nil {
+			return overview, err
+		}
+		overview.ErrorsToday += n
+	}
+	return overview, nil
+}
+
+// lastEntryTime returns the time of the last timestamped entry in the tail
+// of a log file.
+func lastEntryTime(path string) (time.Time, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return time.Time{}, err
+	}
+	offset := max(0, info.Size()-tailSize)
+	tail := make([]byte, info.Size()-offset)
+	if _, err := file.ReadAt(tail, offset); err != nil && err != io.EOF {
+		return time.Time{}, err
+	}
+
+	lines := bytes.Split(tail, []byte("\n"))
+	if offset > 0 {
+		// The first line was probably cut off.
+		lines = lines[1:]
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := bytes.TrimSpace(lines[i])
+		if len(line) == 0 {
+			continue
+		}
+		if entry := logs.Parse(string(line)); !entry.Time.IsZero() {
+			return entry.Time, nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+// countLevel counts the entries of a level in a log file.
+func countLevel(path, level string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	count := 0
+	err = logs.Scan(file, func(entry logs.Entry) error {
+		if entry.Level == level {
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+const (
+	// maxDistinct caps how many distinct values are remembered per field;
+	// past it the cardinality is reported as "at least".
+	maxDistinct = 1000
+	maxExamples = 3
+)
+
+// Field describes one data key as observed across the entries of a report.
+// Nested objects are flattened into dotted keys such as "user.id".
+type Field struct {
+	Key string
+	// Count is how many entries have the key.
+	Count int
+	// Types counts the JSON types seen: string, number, bool, null, object
+	// and array.
+	Types    map[string]int
+	Examples []string
+	distinct map[string]bool
+}
+
+// Cardinality returns the number of distinct values seen; capped is true
+// when there were too many to count.
+func (f *Field) Cardinality() (n int, capped bool) {
+	return len(f.distinct), len(f.distinct) >= maxDistinct
+}
+
+// TypeNames returns the types seen, most common first.
+func (f *Field) TypeNames() []string {
+	var names []string
+	for _, c := range top(f.Types, len(f.Types)) {
+		names = append(names, c.Value)
+	}
+	return names
+}
+
+// MixedTypes reports whether the key has held values of more than one type,
+// not counting null.
+func (f *Field) MixedTypes() bool {
+	n := len(f.Types)
+	if f.Types["null"] > 0 {
+		n--
+	}
+	return n > 1
+}
+
+// valueType names the JSON type of a decoded value.
+func valueType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	return "unknown"
+}
+
+func (r *Report) addFields(prefix string, data map[string]any) {
+	for key, v := range data {
+		key = prefix + key
+		field := r.fields[key]
+		if field == nil {
+			field = &Field{Key: key, Types: map[string]int{}, distinct: map[string]bool{}}
+			r.fields[key] = field
+		}
+		field.Count++
+		field.Types[valueType(v)]++
+
+		if nested, ok := v.(map[string]any); ok {
+			r.addFields(key+".", nested)
+			continue
+		}
+		if number, ok := NumberValue(v); ok {
+			d := r.numbers[key]
+			if d == nil {
+				d = &Distribution{Name: key}
+				r.numbers[key] = d
+			}
+			d.Add(number)
+		}
+		value := "null"
+		if v != nil {
+			value = logs.FormatValue(v)
+		}
+		if !field.distinct[value] && len(field.distinct) < maxDistinct {
+			field.distinct[value] = true
+			if len(field.Examples) < maxExamples {
+				field.Examples = append(field.Examples, value)
+			}
+		}
+	}
+}
+
+// Fields returns every data key seen, sorted by key.
+func (r *Report) Fields() []*Field {
+	fields := make([]*Field, 0, len(r.fields))
+	for _, field := range r.fields {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	return fields
+}
+
+// DuplicateKeys returns sets of keys that only differ in case or
+// separators, such as user_id, userId and userID, each sorted.
+func (r *Report) DuplicateKeys() [][]string {
+	byCanonical := map[string][]string{}
+	for key := range r.fields {
+		canonical := strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "", " ", "").Replace(key))
+		byCanonical[canonical] = append(byCanonical[canonical], key)
+	}
+
+	var duplicates [][]string
+	for _, keys := range byCanonical {
+		if len(keys) > 1 {
+			sort.Strings(keys)
+			duplicates = append(duplicates, keys)
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i][0] < duplicates[j][0] })
+	return duplicates
+}
@@ -0,0 +1,220 @@
+// Package stats summarizes log files: entries per level over time, the most
+// common messages, error rates, file sizes, groups of similar errors and
+// the schema and numeric distributions of the data fields.
+package stats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+)
+
+// Levels are the levels stats are kept for, most severe first.
+var Levels = []string{"ERROR", "WARN", "INFO", "DEBUG"}
+
+// Report is the summary of a set of log files.
+type Report struct {
+	Files   []File
+	Entries int
+	// First and Last are the earliest and latest timestamps seen.
+	First time.Time
+	Last  time.Time
+	// Levels counts entries per level.
+	Levels map[string]int
+	// Hours buckets entries by the hour they were logged in.
+	Hours    map[time.Time]*Hour
+	Messages map[string]int
+	groups   map[string]*Group
+	fields   map[string]*Field
+	numbers  map[string]*Distribution
+}
+
+// File is one file's contribution to a report.
+type File struct {
+	Path    string
+	Size    int64
+	Entries int
+}
+
+// Hour counts the entries logged in one hour.
+type Hour struct {
+	Start  time.Time
+	Total  int
+	Levels map[string]int
+}
+
+// Count is a value and how often it occurred.
+type Count struct {
+	Value string
+	Count int
+}
+
+func newReport() *Report {
+	return &Report{
+		Levels:   map[string]int{},
+		Hours:    map[time.Time]*Hour{},
+		Messages: map[string]int{},
+		groups:   map[string]*Group{},
+		fields:   map[string]*Field{},
+		numbers:  map[string]*Distribution{},
+	}
+}
+
+// Add counts one entry into the report.
+func (r *Report) Add(entry logs.Entry) {
+	r.Entries++
+	if !entry.Valid {
+		return
+	}
+
+	r.Levels[entry.Level]++
+	r.Messages[entry.Message]++
+	r.group(entry)
+	r.addFields("", entry.Data)
+
+	if entry.Time.IsZero() {
+		return
+	}
+	if r.First.IsZero() || entry.Time.Before(r.First) {
+		r.First = entry.Time
+	}
+	if entry.Time.After(r.Last) {
+		r.Last = entry.Time
+	}
+
+	start := entry.Time.Truncate(time.Hour)
+	hour := r.Hours[start]
+	if hour == nil {
+		hour = &Hour{Start: start, Levels: map[string]int{}}
+		r.Hours[start] = hour
+	}
+	hour.Total++
+	hour.Levels[entry.Level]++
+}
+
+// Timeline returns every hour from the first entry to the last, including
+// hours with no entries, oldest first.
+func (r *Report) Timeline() []Hour {
+	if r.First.IsZero() {
+		return nil
+	}
+	var hours []Hour
+	for start := r.First.Truncate(time.Hour); !start.After(r.Last); start = start.Add(time.Hour) {
+		if hour := r.Hours[start]; hour != nil {
+			hours = append(hours, *hour)
+		} else {
+			hours = append(hours, Hour{Start: start, Levels: map[string]int{}})
+		}
+	}
+	return hours
+}
+
+// BusiestHours returns the n hours with the most entries, busiest first.
+func (r *Report) BusiestHours(n int) []Hour {
+	hours := make([]Hour, 0, len(r.Hours))
+	for _, hour := range r.Hours {
+		hours = append(hours, *hour)
+	}
+	sort.Slice(hours, func(i, j int) bool {
+		if hours[i].Total != hours[j].Total {
+			return hours[i].Total > hours[j].Total
+		}
+		return hours[i].Start.Before(hours[j].Start)
+	})
+	return hours[:min(n, len(hours))]
+}
+
+// TopMessages returns the n most frequent messages, most frequent first.
+func (r *Report) TopMessages(n int) []Count {
+	return top(r.Messages, n)
+}
+
+// top sorts counts by frequency, then value, and keeps the first n.
+func top(counts map[string]int, n int) []Count {
+	list := make([]Count, 0, len(counts))
+	for value, count := range counts {
+		list = append(list, Count{value, count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Value < list[j].Value
+	})
+	return list[:min(n, len(list))]
+}
+
+// Collect streams the files into a report. progress, if not nil, is called
+// as bytes are read with the running total and the size of all the files.
+func Collect(paths []string, progress func(done, total int64)) (*Report, error) {
+	report := newReport()
+	files, err := Walk(paths, progress, report.Add)
+	if err != nil {
+		return nil, err
+	}
+	report.Files = files
+	return report, nil
+}
+
+// Walk streams every entry of the files to fn, reporting progress as
+// Collect does, and returns the size and entry count of each file.
+func Walk(paths []string, progress func(done, total int64), fn func(logs.Entry)) ([]File, error) {
+	var files []File
+	var total int64
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		files = append(files, File{Path: path, Size: info.Size()})
+		total += info.Size()
+	}
+
+	var done int64
+	for i, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		counter := &countingReader{r: file}
+		lastReport := time.Now()
+		err = logs.Scan(bufio.NewReader(counter), func(entry logs.Entry) error {
+			fn(entry)
+			files[i].Entries++
+			if progress != nil && time.Since(lastReport) > 100*time.Millisecond {
+				// A file growing while it's read can go past its size.
+				progress(min(done+counter.n, total), total)
+				lastReport = time.Now()
+			}
+			return nil
+		})
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		done += files[i].Size
+		if progress != nil {
+			progress(done, total)
+		}
+	}
+	return files, nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
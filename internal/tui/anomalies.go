@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/detector"
+	"github.com/LFroesch/logdog/internal/stats"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// anomaliesMsg carries the anomalies found in one project's recent logs.
+type anomaliesMsg struct {
+	project   string
+	anomalies []stats.Anomaly
+}
+
+// scanAnomalies looks for anomalies in every project under the log root,
+// one project per command so each shows up as soon as it is read.
+func scanAnomalies(projects []string) tea.Cmd {
+	root, err := detector.LogRoot()
+	if err != nil {
+		return nil
+	}
+	now := time.Now()
+	var cmds []tea.Cmd
+	for _, project := range projects {
+		project := project
+		cmds = append(cmds, func() tea.Msg {
+			// A project that can't be read just shows no anomalies.
+			anomalies, _ := stats.ScanAnomalies(filepath.Join(root, project), now)
+			return anomaliesMsg{project: project, anomalies: anomalies}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m Model) showAnomalies(msg anomaliesMsg) (Model, tea.Cmd) {
+	if m.projectAnomalies == nil {
+		m.projectAnomalies = map[string][]stats.Anomaly{}
+	}
+	m.projectAnomalies[msg.project] = msg.anomalies
+	return m, nil
+}
+
+// anomalyBadge summarizes a project's anomalies for the global list, or is
+// empty when there are none.
+func (m Model) anomalyBadge(project string) string {
+	newGroups, spikes := 0, 0
+	for _, a := range m.projectAnomalies[project] {
+		if a.New {
+			newGroups++
+		} else {
+			spikes++
+		}
+	}
+
+	var parts []string
+	if newGroups > 0 {
+		parts = append(parts, fmt.Sprintf("%d new %s", newGroups, plural(newGroups, "error", "errors")))
+	}
+	if spikes > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", spikes, plural(spikes, "spike", "spikes")))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Foreground(m.theme().Error).
+		Bold(true).
+		Render("⚠ " + strings.Join(parts, ", ") + " in 24h")
+}
+
+// formatAnomaly describes one anomaly on a line.
+func formatAnomaly(a stats.Anomaly) string {
+	if a.New {
+		return fmt.Sprintf("NEW    %-5s %s  (first seen %s, %d since)", a.Group.Level, a.Group.Pattern,
+			a.Hour.Format("Jan 02 15:00"), a.Count)
+	}
+	ratio := "again"
+	if a.Baseline > 0 {
+		ratio = fmt.Sprintf("%.0fx", float64(a.Count)/a.Baseline)
+	}
+	return fmt.Sprintf("%-6s %-5s %s  (%d at %s, usually %.1f/h)", ratio,
+		a.Group.Level, a.Group.Pattern, a.Count, a.Hour.Format("Jan 02 15:00"), a.Baseline)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
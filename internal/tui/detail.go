@@ -0,0 +1,226 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/LFroesch/logdog/internal/logs"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// moveEntryCursor moves the viewer's entry cursor by delta entries and
+// scrolls it into view.
+func (m *Model) moveEntryCursor(delta int) {
+	if len(m.viewEntries) == 0 {
+		m.entryCursor = 0
+		return
+	}
+	m.entryCursor = max(0, min(len(m.viewEntries)-1, m.entryCursor+delta))
+	m.viewer.EnsureVisible(m.entryCursor)
+}
+
+// keepCursorVisible pulls the entry cursor back into the window after the
+// viewer was scrolled by a page or to either end.
+func (m *Model) keepCursorVisible() {
+	_, items := m.viewer.VisibleLines()
+	if len(items) == 0 {
+		m.entryCursor = 0
+		return
+	}
+	m.entryCursor = max(items[0], min(items[len(items)-1], m.entryCursor))
+}
+
+func (m Model) openDetail() (Model, tea.Cmd) {
+	if m.entryCursor >= len(m.viewEntries) {
+		return m, nil
+	}
+	m.detailOpen = true
+	m.detail = viewport{}
+	m.refreshDetail()
+	return m, nil
+}
+
+// refreshDetail renders the entry under the cursor into the detail pane.
+func (m *Model) refreshDetail() {
+	width, height := m.viewer.width, m.viewer.height
+	m.detail.SetSize(width, height)
+	m.detail.SetItems(strings.Split(m.formatEntryDetail(m.viewEntries[m.entryCursor]), "\n"))
+	m.detail.GotoTop()
+}
+
+// handleDetailKey handles keys while the detail pane is open: scrolling the
+// pane, stepping to the neighbouring entries, and closing it.
+func (m Model) handleDetailKey(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	switch msg.String() {
+	case "down", "j":
+		m.detail.ScrollDown(1)
+	case "up", "k":
+		m.detail.ScrollUp(1)
+	case "pgdown", " ", "ctrl+f":
+		m.detail.PageDown()
+	case "pgup", "b", "ctrl+b":
+		m.detail.PageUp()
+	case "ctrl+d":
+		m.detail.HalfPageDown()
+	case "ctrl+u":
+		m.detail.HalfPageUp()
+	case "g", "home":
+		m.detail.GotoTop()
+	case "G", "end":
+		m.detail.GotoBottom()
+	case "right", "l":
+		m.moveEntryCursor(1)
+		m.refreshDetail()
+	case "left", "h":
+		m.moveEntryCursor(-1)
+		m.refreshDetail()
+	case "esc", "enter":
+		m.detailOpen = false
+	case "r":
+		m, cmd := m.startTrace()
+		return &m, cmd
+	case "q", "ctrl+c":
+		return nil, nil
+	}
+	return &m, nil
+}
+
+func (m Model) formatEntryDetail(entry logs.Entry) string {
+	label := lipgloss.NewStyle().
+		Foreground(m.theme().Muted).
+		Width(11)
+	text := lipgloss.NewStyle().
+		Foreground(m.theme().Text)
+
+	if !entry.Valid {
+		return label.Render("Raw") + "\n" + text.Render(entry.Raw)
+	}
+
+	var b strings.Builder
+
+	timestamp := entry.Timestamp
+	if !entry.Time.IsZero() {
+		timestamp = entry.Time.Format("Monday, January 2 2006 15:04:05 MST")
+	}
+	b.WriteString(label.Render("Timestamp") + text.Render(timestamp) + "\n")
+
+	if m.multiSource() && entry.Source != "" {
+		b.WriteString(label.Render("Project") + m.sourceStyle(entry.Source).Render(entry.Source) + "\n")
+	}
+	b.WriteString(label.Render("Level") + lipgloss.NewStyle().
+		Foreground(m.theme().levelColor(entry.Level)).
+		Bold(true).
+		Render(entry.Level) + "\n")
+
+	b.WriteString("\n" + label.Render("Message") + "\n")
+	for _, line := range strings.Split(entry.Message, "\n") {
+		b.WriteString(text.Render(line) + "\n")
+	}
+
+	if len(entry.Data) > 0 {
+		b.WriteString("\n" + label.Render("Data") + "\n")
+		b.WriteString(m.formatJSON(entry.Data, ""))
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatJSON pretty-prints a decoded JSON value with syntax colors, one key
+// or element per line with nested objects expanded.
+func (m Model) formatJSON(v any, indent string) string {
+	punct := lipgloss.NewStyle().Foreground(m.theme().Muted)
+	inner := indent + "  "
+
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			return punct.Render("{}")
+		}
+		keyStyle := lipgloss.NewStyle().Foreground(m.theme().Accent)
+
+		var b strings.Builder
+		b.WriteString(punct.Render("{") + "\n")
+		for i, k := range sortedKeys(v) {
+			b.WriteString(inner + keyStyle.Render(quoteJSON(k)) + punct.Render(": "))
+			b.WriteString(m.formatJSON(v[k], inner))
+			if i < len(v)-1 {
+				b.WriteString(punct.Render(","))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString(indent + punct.Render("}"))
+		return b.String()
+	case []any:
+		if len(v) == 0 {
+			return punct.Render("[]")
+		}
+
+		var b strings.Builder
+		b.WriteString(punct.Render("[") + "\n")
+		for i, elem := range v {
+			b.WriteString(inner + m.formatJSON(elem, inner))
+			if i < len(v)-1 {
+				b.WriteString(punct.Render(","))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString(indent + punct.Render("]"))
+		return b.String()
+	case string:
+		return lipgloss.NewStyle().Foreground(m.theme().Info).Render(quoteJSON(v))
+	case float64:
+		return lipgloss.NewStyle().Foreground(m.theme().Warn).Render(logs.FormatValue(v))
+	case json.Number:
+		return lipgloss.NewStyle().Foreground(m.theme().Warn).Render(v.String())
+	default:
+		// true, false and null
+		encoded, _ := json.Marshal(v)
+		return lipgloss.NewStyle().Foreground(m.theme().Message).Render(string(encoded))
+	}
+}
+
+// quoteJSON quotes s as a JSON string, leaving <, > and & alone.
+func quoteJSON(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func (m Model) renderDetail() string {
+	entry := m.viewEntries[m.entryCursor]
+
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme().Accent).
+		Render(fmt.Sprintf("📋 Entry %d of %d", m.entryCursor+1, len(m.viewEntries)))
+	if entry.Level != "" {
+		header += "  " + lipgloss.NewStyle().
+			Foreground(m.theme().levelColor(entry.Level)).
+			Render(entry.Level)
+	}
+	header = m.fitWidth(header)
+
+	visible, _ := m.detail.VisibleLines()
+	lines := append([]string(nil), visible...)
+	for len(lines) < m.detail.visibleHeight() {
+		lines = append(lines, "")
+	}
+
+	first, last, total, percent := m.detail.Position()
+	footer := fmt.Sprintf("lines %d-%d of %d (%d%%)", first, last, total, percent) +
+		"  •  j/k PgUp/PgDn scroll  h/l previous/next entry  r trace  ENTER/ESC close"
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme().Muted).
+		Render(m.fitWidth(footer))
+
+	messageStr := "\n" + lipgloss.NewStyle().
+		Foreground(m.theme().Message).
+		Render(m.fitWidth(m.message))
+
+	return fmt.Sprintf("%s\n\n%s\n\n%s%s", header, strings.Join(lines, "\n"), instructions, messageStr)
+}
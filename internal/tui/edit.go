@@ -0,0 +1,112 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startEdit puts the model into inline editing mode for the field under the
+// cursor. While editing, every key goes to the field until ENTER or ESC.
+func (m Model) startEdit(value string) (Model, tea.Cmd) {
+	m.editing = true
+	m.editValue = value
+	m.message = ""
+	return m, nil
+}
+
+func (m Model) handleEditKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.editing = false
+		return m.commitEdit(m.editValue)
+	case tea.KeyEsc:
+		m.editing = false
+		m.editValue = ""
+		return m.cancelEdit(), nil
+	case tea.KeyBackspace:
+		if runes := []rune(m.editValue); len(runes) > 0 {
+			m.editValue = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		m.editValue = ""
+	case tea.KeySpace:
+		m.editValue += " "
+	case tea.KeyRunes:
+		m.editValue += string(msg.Runes)
+	case tea.KeyCtrlR:
+		if m.searching {
+			return m.toggleSearchRegex(), nil
+		}
+		return m, nil
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	default:
+		return m, nil
+	}
+	return m.editChanged(), nil
+}
+
+// editChanged runs after every keystroke that changes the edit buffer, for
+// fields that react while typing.
+func (m Model) editChanged() Model {
+	if m.searching {
+		return m.previewSearch(m.editValue)
+	}
+	return m
+}
+
+// cancelEdit undoes anything editChanged did once editing is abandoned.
+func (m Model) cancelEdit() Model {
+	m.filtering = false
+	m.editingColumns = false
+	m.querying = false
+	m.jumping = false
+	m.analyzing = false
+	m.renamingProject = false
+	m.mergingProject = false
+	if m.searching {
+		return m.cancelSearch()
+	}
+	return m
+}
+
+// commitEdit stores an edited value into whichever field was being edited.
+func (m Model) commitEdit(value string) (Model, tea.Cmd) {
+	if m.querying {
+		return m.commitQuery(value)
+	}
+
+	switch m.screen {
+	case screenLogView:
+		if m.filtering {
+			return m.commitFilter(value)
+		}
+		if m.editingColumns {
+			return m.commitColumns(value)
+		}
+		if m.jumping {
+			return m.commitJump(value)
+		}
+		return m.commitSearch(value)
+	case screenInstall:
+		return m.commitInstallField(value)
+	case screenSettings:
+		return m.commitSettingsField(value)
+	case screenNumbers:
+		return m.commitNumbers(value)
+	case screenGlobalProjects:
+		if m.mergingProject {
+			return m.commitProjectMerge(value)
+		}
+		return m.commitProjectRename(value)
+	}
+	return m, nil
+}
+
+// fieldValue renders a form value, showing the edit buffer and a cursor when
+// the field is being edited.
+func (m Model) fieldValue(selected bool, value string) string {
+	if selected && m.editing {
+		return m.editValue + "█"
+	}
+	return value
+}
@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/LFroesch/logdog/internal/logs"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fieldFilter keeps entries whose data[Key] equals Value (or doesn't, when
+// Negate is set).
+type fieldFilter struct {
+	Key    string
+	Value  string
+	Negate bool
+}
+
+func (f fieldFilter) String() string {
+	if f.Negate {
+		return f.Key + "!=" + f.Value
+	}
+	return f.Key + "=" + f.Value
+}
+
+func (f fieldFilter) matches(entry logs.Entry) bool {
+	v, ok := entry.DataValue(f.Key)
+	equal := ok && logs.FormatValue(v) == f.Value
+	// Numbers are equal however they are written, e.g. 1e3 and 1000.
+	if number, isNumber := v.(float64); ok && isNumber {
+		if value, err := strconv.ParseFloat(f.Value, 64); err == nil {
+			equal = number == value
+		}
+	}
+	return equal != f.Negate
+}
+
+// parseFieldFilters parses space-separated key=value or key!=value terms.
+func parseFieldFilters(input string) ([]fieldFilter, error) {
+	var filters []fieldFilter
+	for _, term := range strings.Fields(input) {
+		if key, value, ok := strings.Cut(term, "!="); ok && key != "" {
+			filters = append(filters, fieldFilter{Key: key, Value: value, Negate: true})
+			continue
+		}
+		key, value, ok := strings.Cut(term, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%q is not a key=value filter", term)
+		}
+		filters = append(filters, fieldFilter{Key: key, Value: value})
+	}
+	return filters, nil
+}
+
+// levelToggleKeys maps the number keys to the level each one shows or hides.
+var levelToggleKeys = map[string]string{
+	"1": "ERROR",
+	"2": "WARN",
+	"3": "INFO",
+	"4": "DEBUG",
+}
+
+// hiddenLevelsBelow hides every level below min, the viewer's default.
+func hiddenLevelsBelow(min string) map[string]bool {
+	hidden := map[string]bool{}
+	for _, level := range logLevels {
+		if !levelAtLeast(level, min) {
+			hidden[level] = true
+		}
+	}
+	return hidden
+}
+
+// entryVisible applies the level toggles and field filters to an entry.
+func (m Model) entryVisible(entry logs.Entry) bool {
+	if !entry.Valid {
+		return len(m.fieldFilters) == 0
+	}
+	if m.hiddenLevels[strings.ToUpper(entry.Level)] {
+		return false
+	}
+	for _, f := range m.fieldFilters {
+		if !f.matches(entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyFilters rebuilds the visible entries from everything loaded, keeping
+// the viewer on the same entry (or the next visible one) where possible.
+func (m *Model) applyFilters() {
+	anchor, selected := 0, 0
+	if first := m.viewer.FirstItem(); first < len(m.viewSource) {
+		anchor = m.viewSource[first]
+	}
+	if m.entryCursor < len(m.viewSource) {
+		selected = m.viewSource[m.entryCursor]
+	}
+
+	m.viewEntries = m.viewEntries[:0]
+	m.viewSource = m.viewSource[:0]
+	top, cursor := -1, -1
+	for i, entry := range m.allEntries {
+		if !m.entryVisible(entry) {
+			continue
+		}
+		if top < 0 && i >= anchor {
+			top = len(m.viewEntries)
+		}
+		if cursor < 0 && i >= selected {
+			cursor = len(m.viewEntries)
+		}
+		m.viewEntries = append(m.viewEntries, entry)
+		m.viewSource = append(m.viewSource, i)
+	}
+
+	if m.sorted() {
+		moved := m.sortEntries()
+		if top >= 0 {
+			top = moved[top]
+		}
+		if cursor >= 0 {
+			cursor = moved[cursor]
+		}
+	}
+
+	m.findMatches()
+	m.refreshViewer()
+	if top < 0 {
+		m.viewer.GotoBottom()
+	} else {
+		m.viewer.ScrollToItem(top)
+	}
+	if cursor < 0 {
+		cursor = len(m.viewEntries) - 1
+	}
+	m.entryCursor = max(0, cursor)
+	m.keepCursorVisible()
+}
+
+func (m Model) toggleLevel(level string) (Model, tea.Cmd) {
+	if m.hiddenLevels == nil {
+		m.hiddenLevels = map[string]bool{}
+	}
+	m.hiddenLevels[level] = !m.hiddenLevels[level]
+	m.applyFilters()
+
+	state := "shown"
+	if m.hiddenLevels[level] {
+		state = "hidden"
+	}
+	m.message = fmt.Sprintf("%s entries %s", level, state)
+	return m, nil
+}
+
+// startFilter opens the field filter prompt, pre-filled with the active
+// filters so they can be edited.
+func (m Model) startFilter() (Model, tea.Cmd) {
+	m.filtering = true
+	var terms []string
+	for _, f := range m.fieldFilters {
+		terms = append(terms, f.String())
+	}
+	return m.startEdit(strings.Join(terms, " "))
+}
+
+func (m Model) commitFilter(input string) (Model, tea.Cmd) {
+	m.filtering = false
+	filters, err := parseFieldFilters(input)
+	if err != nil {
+		m.message = fmt.Sprintf("❌ %v", err)
+		return m, nil
+	}
+
+	m.fieldFilters = filters
+	m.applyFilters()
+	if len(filters) == 0 {
+		m.message = "Field filters cleared"
+	} else {
+		m.message = fmt.Sprintf("%d of %d entries match", len(m.viewEntries), len(m.allEntries))
+	}
+	return m, nil
+}
+
+// filterSummary describes the active level toggles and field filters for
+// the viewer header, or "" when everything is shown.
+func (m Model) filterSummary() string {
+	var parts []string
+
+	var shown []string
+	for _, level := range []string{"ERROR", "WARN", "INFO", "DEBUG"} {
+		if !m.hiddenLevels[level] {
+			shown = append(shown, level)
+		}
+	}
+	if len(shown) < 4 {
+		if len(shown) == 0 {
+			shown = []string{"none"}
+		}
+		parts = append(parts, "levels: "+strings.Join(shown, " "))
+	}
+
+	for _, f := range m.fieldFilters {
+		parts = append(parts, f.String())
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[%s]  %d/%d entries", strings.Join(parts, "] ["), len(m.viewEntries), len(m.allEntries))
+}
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const followInterval = 500 * time.Millisecond
+
+// followTickMsg asks the viewer to poll the followed file. id ties the tick
+// to the follow session that scheduled it, so ticks from an earlier session
+// are dropped instead of starting a second polling loop.
+type followTickMsg struct {
+	id int
+}
+
+func followTick(id int) tea.Cmd {
+	return tea.Tick(followInterval, func(time.Time) tea.Msg {
+		return followTickMsg{id: id}
+	})
+}
+
+func (m Model) toggleFollow() (Model, tea.Cmd) {
+	if m.following {
+		m.following = false
+		m.message = "Stopped following"
+		return m, nil
+	}
+	if m.activeQuery != nil {
+		m.message = "Query results can't be followed, press ESC to go back to the file"
+		return m, nil
+	}
+	if m.activeGroup != nil {
+		m.message = "Error groups can't be followed, press ESC to go back to the groups"
+		return m, nil
+	}
+	if m.timeline != nil {
+		m.message = "The timeline can't be followed, open today's file instead"
+		return m, nil
+	}
+
+	m.following = true
+	m.followID++
+	m.followDay = today()
+	m.viewer.GotoBottom()
+	m.message = "Following " + filepath.Base(m.viewingFile)
+	return m, followTick(m.followID)
+}
+
+// pollFollow reads whatever has been appended to the followed file since the
+// last poll, and hops to the next day's file at midnight.
+func (m Model) pollFollow(msg followTickMsg) (Model, tea.Cmd) {
+	if !m.following || msg.id != m.followID || m.screen != screenLogView {
+		return m, nil
+	}
+
+	if day := today(); !day.Equal(m.followDay) {
+		m = m.hopToDay(day)
+	}
+
+	if info, err := os.Stat(m.viewingFile); err == nil && info.Size() < m.followOffset {
+		// The file was truncated or replaced; start again from the top.
+		m.followOffset = 0
+	}
+
+	entries, offset, err := logs.ReadFrom(m.viewingFile, m.followOffset)
+	if err != nil && !os.IsNotExist(err) {
+		m.message = fmt.Sprintf("❌ Error following log: %v", err)
+	}
+	m.followOffset = offset
+
+	if len(entries) > 0 {
+		m.appendEntries(entries)
+	}
+	return m, followTick(m.followID)
+}
+
+// hopToDay switches from the followed day's file to the file for day, if the
+// file being followed was the one for the day that just ended.
+func (m Model) hopToDay(day time.Time) Model {
+	project, fileDay, ok := logs.ParseDailyFileName(filepath.Base(m.viewingFile))
+	if ok && fileDay.Equal(m.followDay) {
+		// Pick up anything written just before midnight first.
+		if entries, _, err := logs.ReadFrom(m.viewingFile, m.followOffset); err == nil && len(entries) > 0 {
+			m.appendEntries(entries)
+		}
+		m.viewingFile = filepath.Join(filepath.Dir(m.viewingFile), logs.DailyFileName(project, day))
+		m.followOffset = 0
+		m.message = "New day, following " + filepath.Base(m.viewingFile)
+	}
+	m.followDay = day
+	return m
+}
+
+// appendEntries adds newly read entries to the viewer, staying pinned to the
+// bottom (with the cursor on the newest entry) if the viewer was already
+// there.
+func (m *Model) appendEntries(entries []logs.Entry) {
+	atBottom := m.viewer.AtBottom()
+
+	if m.sorted() {
+		// New entries land wherever the sort puts them.
+		m.allEntries = append(m.allEntries, entries...)
+		m.applyFilters()
+		return
+	}
+
+	for _, entry := range entries {
+		m.allEntries = append(m.allEntries, entry)
+		if m.entryVisible(entry) {
+			m.viewEntries = append(m.viewEntries, entry)
+			m.viewSource = append(m.viewSource, len(m.allEntries)-1)
+		}
+	}
+
+	m.findMatches()
+	m.refreshViewer()
+	if atBottom {
+		m.viewer.GotoBottom()
+		m.entryCursor = max(0, len(m.viewEntries)-1)
+	}
+}
+
+func today() time.Time {
+	y, mo, d := time.Now().Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, time.Local)
+}
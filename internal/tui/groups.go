@@ -0,0 +1,248 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+	"github.com/LFroesch/logdog/internal/stats"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// groupSampleLines is how many lines the selected group's sample takes
+// under the list.
+const groupSampleLines = 5
+
+// groupEntriesMsg carries the entries of the group being opened; id ties it
+// to the stats screen it was opened from.
+type groupEntriesMsg struct {
+	id      int
+	group   *stats.Group
+	entries []logs.Entry
+	err     error
+}
+
+func (m Model) handleGroupsKey(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	count := len(m.groups)
+
+	switch msg.String() {
+	case "esc":
+		m.screen = screenLogs
+		m.statsID++
+		m.statsReport = nil
+		m.groups = nil
+	case "r":
+		m, cmd := m.openStats(screenGroups)
+		return &m, cmd
+	case "up", "k":
+		m.groupCursor = max(0, m.groupCursor-1)
+	case "down", "j":
+		m.groupCursor = max(0, min(count-1, m.groupCursor+1))
+	case "pgup", "b":
+		m.groupCursor = max(0, m.groupCursor-m.groupRows())
+	case "pgdown", "f", " ":
+		m.groupCursor = max(0, min(count-1, m.groupCursor+m.groupRows()))
+	case "g", "home":
+		m.groupCursor = 0
+	case "G", "end":
+		m.groupCursor = max(0, count-1)
+	case "enter":
+		if m.groupCursor < count {
+			m, cmd := m.openGroup()
+			return &m, cmd
+		}
+	default:
+		return nil, nil
+	}
+	m.message = ""
+	return &m, nil
+}
+
+// groupRows is how many groups fit on the screen at once.
+func (m Model) groupRows() int {
+	if m.height <= 0 {
+		return defaultViewportHeight - groupSampleLines
+	}
+	return max(3, m.height-8-groupSampleLines)
+}
+
+// openGroup reads the selected group's entries back from the log files in
+// the background, since the report only keeps a few samples of each.
+func (m Model) openGroup() (Model, tea.Cmd) {
+	group := m.groups[m.groupCursor]
+	m.message = fmt.Sprintf("Reading %d entries...", group.Count)
+	return m, readGroup(m.statsID, group, append([]string(nil), m.logFiles...))
+}
+
+func readGroup(id int, group *stats.Group, files []string) tea.Cmd {
+	return func() tea.Msg {
+		var entries []logs.Entry
+		_, err := stats.Walk(files, nil, func(entry logs.Entry) {
+			if fingerprint, ok := stats.Fingerprint(entry); ok && fingerprint == group.Fingerprint {
+				entries = append(entries, entry)
+			}
+		})
+		return groupEntriesMsg{id: id, group: group, entries: entries, err: err}
+	}
+}
+
+// showGroup shows a group's entries in the viewer, unless the groups screen
+// has been left or refreshed since it was opened.
+func (m Model) showGroup(msg groupEntriesMsg) (Model, tea.Cmd) {
+	if msg.id != m.statsID || m.screen != screenGroups {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.message = fmt.Sprintf("❌ Failed to read the group's entries: %v", msg.err)
+		return m, nil
+	}
+
+	group := msg.group
+	m.activeGroup = group
+	m.activeQuery = nil
+	m.activeTrace = nil
+	m.timeline = nil
+	m.viewingFile = ""
+	m.viewSources = []logs.Source{fileSource(m.logFiles[0])}
+	m.showEntries(msg.entries)
+	m.message = ""
+	return m, nil
+}
+
+// closeGroup goes back from a group's entries to the list of groups.
+func (m Model) closeGroup() (Model, tea.Cmd) {
+	m.activeGroup = nil
+	m.following = false
+	m.screen = screenGroups
+	m.message = ""
+	return m, nil
+}
+
+// groupTitle is the viewer header while showing a group's entries.
+func (m Model) groupTitle() string {
+	return fmt.Sprintf("🐞 %s: [%s] %s  (%d entries)", m.viewerProject(), m.activeGroup.Level, m.activeGroup.Pattern, len(m.allEntries))
+}
+
+func (m Model) renderGroups() string {
+	title := "🐞 Error groups: " + m.statsProject()
+	if m.statsReport != nil {
+		entries := 0
+		for _, group := range m.groups {
+			entries += group.Count
+		}
+		title += fmt.Sprintf("  (%d groups, %d entries)", len(m.groups), entries)
+	}
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme().Accent).
+		Render(title)
+
+	var body, footer string
+	switch {
+	case m.statsReport == nil:
+		body = m.renderStatsProgress()
+		footer = "Reading log files...  •  ESC back"
+	case len(m.groups) == 0:
+		body = "No ERROR or WARN entries found"
+		footer = "r refresh  ESC back"
+	default:
+		body = m.renderGroupList()
+		footer = fmt.Sprintf("group %d of %d", m.groupCursor+1, len(m.groups)) +
+			"  •  j/k move  PgUp/PgDn g/G scroll  ENTER entries  r refresh  ESC back"
+	}
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme().Muted).
+		Render(m.fitWidth(footer))
+
+	messageStr := "\n" + lipgloss.NewStyle().
+		Foreground(m.theme().Message).
+		Render(m.fitWidth(m.message))
+
+	return fmt.Sprintf("%s\n\n%s\n\n%s%s", header, body, instructions, messageStr)
+}
+
+// renderGroupList draws the window of groups around the cursor, followed by
+// the selected group's sample entry.
+func (m Model) renderGroupList() string {
+	width := 80
+	if m.width > 0 {
+		width = max(40, m.width-6)
+	}
+	groups := m.groups
+	rows := m.groupRows()
+	start := max(0, min(m.groupCursor-rows/2, len(groups)-rows))
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(m.theme().SelectedBg).
+		Foreground(m.theme().SelectedFg)
+	muted := lipgloss.NewStyle().Foreground(m.theme().Muted)
+
+	patternWidth := max(10, width-2-6-2-5-2-12-2-12-2)
+	lines := []string{muted.Render(fmt.Sprintf("  %6s  %-5s  %-12s  %-12s  %s", "COUNT", "LEVEL", "FIRST SEEN", "LAST SEEN", "PATTERN"))}
+	for i := start; i < min(len(groups), start+rows); i++ {
+		group := groups[i]
+		pattern := group.Pattern
+		if group.Caller != "" {
+			pattern += "  @ " + group.Caller
+		}
+		row := func(level string) string {
+			return fmt.Sprintf("%6d  %s  %-12s  %-12s  %s", group.Count, level,
+				formatSeen(group.First), formatSeen(group.Last), cell(pattern, patternWidth))
+		}
+
+		if i == m.groupCursor {
+			lines = append(lines, selectedStyle.Render("> "+row(cell(group.Level, 5))))
+		} else {
+			level := lipgloss.NewStyle().Foreground(m.theme().levelColor(group.Level)).Render(cell(group.Level, 5))
+			lines = append(lines, lipgloss.NewStyle().Foreground(m.theme().Text).Render("  "+row(level)))
+		}
+	}
+	for len(lines) < rows+1 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, m.renderGroupSample(groups[m.groupCursor], width)...)
+	return strings.Join(lines, "\n")
+}
+
+// renderGroupSample shows the most recent entry of a group, with its error
+// field when it has one, and the messages of the other samples kept.
+func (m Model) renderGroupSample(group *stats.Group, width int) []string {
+	muted := lipgloss.NewStyle().Foreground(m.theme().Muted)
+	sample := group.Samples[0]
+	lines := []string{
+		muted.Render(cell("Latest: "+sample.Timestamp, width)),
+		lipgloss.NewStyle().Foreground(m.theme().levelColor(group.Level)).
+			Render(cell(strings.Join(strings.Fields(sample.Message), " "), width)),
+	}
+	var details []string
+	for _, key := range []string{"caller", "error"} {
+		if v, ok := sample.Data[key]; ok {
+			details = append(details, key+"="+cellText(v))
+		}
+	}
+	lines = append(lines, muted.Render(cell(strings.Join(details, "  "), width)))
+
+	var earlier []string
+	for _, entry := range group.Samples[1:] {
+		earlier = append(earlier, strings.Join(strings.Fields(entry.Message), " "))
+	}
+	if len(earlier) > 0 {
+		lines = append(lines, muted.Render(cell("Earlier: "+strings.Join(earlier, "  •  "), width)))
+	} else {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// formatSeen renders a first/last seen time, or a dash when unknown.
+func formatSeen(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("Jan 02 15:04")
+}
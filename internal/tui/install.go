@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Install wizard fields, in display order.
+const (
+	installFieldDir = iota
+	installFieldName
+	installFieldInternal
+	installFieldDryRun
+	installFieldCount
+)
+
+// splitInternal separates a package path into the part after internal/ and
+// whether it is internal at all.
+func splitInternal(pkgPath string) (string, bool) {
+	if pkgPath == "internal" {
+		return "", true
+	}
+	if rest, ok := strings.CutPrefix(pkgPath, "internal/"); ok {
+		return rest, true
+	}
+	return pkgPath, false
+}
+
+func joinInternal(dir string, internal bool) string {
+	dir = strings.Trim(strings.TrimSpace(dir), "/")
+	if !internal {
+		return dir
+	}
+	if dir == "internal" || strings.HasPrefix(dir, "internal/") {
+		return dir
+	}
+	return path.Join("internal", dir)
+}
+
+func (m Model) handleInstallField() (Model, tea.Cmd) {
+	dir, internal := splitInternal(m.config.PackagePath)
+
+	switch m.cursor {
+	case installFieldDir:
+		return m.startEdit(dir)
+	case installFieldName:
+		return m.startEdit(m.config.PackageName)
+	case installFieldInternal:
+		m.config.PackagePath = joinInternal(dir, !internal)
+	case installFieldDryRun:
+		m.dryRun = !m.dryRun
+	}
+	m.message = ""
+	return m, nil
+}
+
+func (m Model) commitInstallField(value string) (Model, tea.Cmd) {
+	value = strings.TrimSpace(value)
+
+	switch m.cursor {
+	case installFieldDir:
+		_, internal := splitInternal(m.config.PackagePath)
+		if joined := joinInternal(value, internal); joined != "" {
+			m.config.PackagePath = joined
+		} else {
+			m.message = "❌ Directory cannot be empty"
+		}
+	case installFieldName:
+		if value != "" {
+			m.config.PackageName = value
+		} else {
+			m.message = "❌ Package name cannot be empty"
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderInstall() string {
+	if m.language == nil {
+		return "No supported language detected. Press ESC to go back."
+	}
+
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme().Accent).
+		Render(fmt.Sprintf("📦 Install logger for %s project", m.language.Name()))
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(m.theme().SelectedBg).
+		Foreground(m.theme().SelectedFg)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(m.theme().Text)
+
+	dir, internal := splitInternal(m.config.PackagePath)
+	fields := []struct {
+		label string
+		value string
+	}{
+		{"Directory:", dir},
+		{"Package name:", m.config.PackageName},
+		{"Internal:", yesNo(internal)},
+		{"Dry run:", onOff(m.dryRun)},
+	}
+
+	var rows []string
+	for i, field := range fields {
+		row := fmt.Sprintf("%-15s %s", field.label, m.fieldValue(i == m.cursor, field.value))
+		if i == m.cursor {
+			rows = append(rows, selectedStyle.Render("> "+row))
+		} else {
+			rows = append(rows, normalStyle.Render("  "+row))
+		}
+	}
+
+	var importLine string
+	if importPath, err := m.language.ImportPath(m.projectPath, m.config); err == nil {
+		importLine = fmt.Sprintf("Import path: %s", importPath)
+	} else {
+		importLine = fmt.Sprintf("Import path: unavailable (%v)", err)
+	}
+
+	info := fmt.Sprintf("\n%s\nThis will create %s/logger.go and %s/README.md",
+		importLine, m.config.PackagePath, m.config.PackagePath)
+
+	help := "Press SPACE to edit/toggle a field, ENTER to install, 'd' to toggle dry run, ESC to cancel"
+	if m.editing {
+		help = "Type a value, ENTER to save, ESC to discard"
+	}
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme().Muted).
+		Render("\n" + help)
+
+	messageStr := ""
+	if m.message != "" {
+		messageStr = "\n\n" + lipgloss.NewStyle().
+			Foreground(m.theme().Message).
+			Render(m.message)
+	}
+
+	return fmt.Sprintf("%s\n\n%s\n%s%s%s", header, strings.Join(rows, "\n"), info, instructions, messageStr)
+}
+
+func (m Model) handleInstall() (Model, tea.Cmd) {
+	if m.language != nil {
+		err := m.language.Install(m.projectPath, m.config)
+		if err != nil {
+			m.message = fmt.Sprintf("❌ Error: %v", err)
+		} else {
+			m.message = fmt.Sprintf("✅ Logger installed successfully! Check %s/logger.go", m.config.PackagePath)
+			if importPath, err := m.language.ImportPath(m.projectPath, m.config); err == nil {
+				m.message += fmt.Sprintf("\nImport it with: import %q", importPath)
+			}
+			if err := m.saveProjectConfig(); err != nil {
+				m.message += fmt.Sprintf("\n❌ %v", err)
+			}
+			m.logFiles = m.language.GetLogPaths(m.projectPath)
+		}
+	} else {
+		m.message = "❌ No supported language detected"
+	}
+	m.screen = screenMain
+	m.cursor = 0
+	m.installPlan = ""
+	return m, tea.ClearScreen
+}
+
+func (m Model) handleDryRun() (Model, tea.Cmd) {
+	if m.language == nil {
+		m.message = "❌ No supported language detected"
+		return m, nil
+	}
+
+	plan, err := m.language.PlanInstall(m.projectPath, m.config)
+	if err != nil {
+		m.message = fmt.Sprintf("❌ Error: %v", err)
+		return m, nil
+	}
+
+	m.installPlan = plan.String()
+	m.screen = screenInstallPlan
+	m.cursor = 0
+	return m, nil
+}
+
+func (m Model) renderInstallPlan() string {
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme().Accent).
+		Render("📝 Dry run: nothing has been written yet")
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme().Muted).
+		Render("Press ENTER to install, ESC to go back")
+
+	content := lipgloss.NewStyle().
+		Foreground(m.theme().Text).
+		Render(m.installPlan)
+
+	return fmt.Sprintf("%s\n\n%s\n\n%s", header, content, instructions)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
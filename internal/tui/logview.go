@@ -0,0 +1,387 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (m Model) handleViewLog() (Model, tea.Cmd) {
+	if m.cursor < len(m.logFiles) {
+		return m.viewLogContent()
+	}
+	return m, nil
+}
+
+func (m Model) viewLogContent() (Model, tea.Cmd) {
+	m, cmd := m.openLogFile(m.logFiles[m.cursor])
+	if m.screen == screenLogView {
+		m.restorePosition(m.viewingFile)
+	}
+	return m, cmd
+}
+
+func (m Model) openLogFile(filePath string) (Model, tea.Cmd) {
+	entries, offset, err := logs.ReadFrom(filePath, 0)
+	if err != nil {
+		m.message = fmt.Sprintf("❌ Error reading log: %v", err)
+		return m, nil
+	}
+
+	m.viewingFile = filePath
+	m.viewSources = []logs.Source{fileSource(filePath)}
+	m.followOffset = offset
+	m.activeQuery = nil
+	m.activeTrace = nil
+	m.activeGroup = nil
+	m.timeline = nil
+	m.showEntries(entries)
+	m.cursor = 0
+
+	return m, nil
+}
+
+// showEntries opens the viewer on entries, starting over with the search,
+// cursor and follow state.
+func (m *Model) showEntries(entries []logs.Entry) {
+	m.screen = screenLogView
+	m.allEntries = entries
+	m.following = false
+	m.entryCursor = 0
+	m.detailOpen = false
+	m.loadTableSettings()
+	m.clearSearch()
+	m.viewer = viewport{}
+	m.applyFilters()
+	m.resizeViewer()
+}
+
+// refreshViewer re-renders every entry into the viewer, e.g. after the
+// search highlighting changed.
+func (m *Model) refreshViewer() {
+	items := make([]string, len(m.viewEntries))
+	var steps []string
+	if m.activeTrace != nil {
+		steps = m.traceSteps()
+	}
+	if m.tableMode {
+		widths, messageWidth := m.tableLayout()
+		for i, entry := range m.viewEntries {
+			items[i] = m.formatTableRow(entry, widths, messageWidth)
+			if steps != nil {
+				items[i] = steps[i] + " " + items[i]
+			}
+		}
+	} else {
+		for i, entry := range m.viewEntries {
+			items[i] = m.formatLogEntry(entry)
+			if steps != nil {
+				items[i] = steps[i] + " " + items[i]
+			}
+		}
+	}
+	m.viewer.SetItems(items)
+}
+
+func (m Model) formatLogEntry(entry logs.Entry) string {
+	if !entry.Valid {
+		return m.highlight(entry.Raw, lipgloss.NewStyle())
+	}
+
+	var result strings.Builder
+
+	if entry.Timestamp != "" {
+		if t, err := time.Parse(time.RFC3339, entry.Timestamp); err == nil {
+			humanTime := t.Format("Jan 02 15:04:05")
+			result.WriteString(lipgloss.NewStyle().
+				Foreground(m.theme().Muted).
+				Render(humanTime))
+		} else {
+			result.WriteString(lipgloss.NewStyle().
+				Foreground(m.theme().Muted).
+				Render(entry.Timestamp))
+		}
+		result.WriteString(" ")
+	}
+
+	if m.multiSource() && entry.Source != "" {
+		result.WriteString(m.sourceStyle(entry.Source).
+			Bold(true).
+			Render(entry.Source))
+		result.WriteString(" ")
+	}
+
+	if entry.Level != "" {
+		result.WriteString(lipgloss.NewStyle().
+			Foreground(m.theme().levelColor(entry.Level)).
+			Bold(true).
+			Render(fmt.Sprintf("[%s]", entry.Level)))
+		result.WriteString(" ")
+	}
+
+	if entry.Message != "" {
+		result.WriteString(m.highlight(entry.Message, lipgloss.NewStyle().
+			Foreground(m.theme().Text)))
+	}
+
+	if len(entry.Data) > 0 {
+		dataStyle := lipgloss.NewStyle().
+			Foreground(m.theme().Accent)
+
+		result.WriteString(" ")
+		result.WriteString(dataStyle.Render("{"))
+		for i, k := range sortedKeys(entry.Data) {
+			if i > 0 {
+				result.WriteString(dataStyle.Render(", "))
+			}
+			result.WriteString(dataStyle.Render(k + "="))
+			result.WriteString(m.highlight(logs.FormatValue(entry.Data[k]), dataStyle))
+		}
+		result.WriteString(dataStyle.Render("}"))
+	}
+
+	return result.String()
+}
+
+func sortedKeys(data map[string]any) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resizeViewer fits the log viewer to the terminal, leaving room for the
+// header and footer around it (and the column titles in the table view).
+func (m *Model) resizeViewer() {
+	width, height := 0, 0
+	if m.width > 0 {
+		width = max(20, m.width-6)
+	}
+	if m.height > 0 {
+		height = max(3, m.height-8)
+		if m.tableMode {
+			height = max(3, height-1)
+		}
+	}
+	m.viewer.SetSize(width, height)
+	m.detail.SetSize(width, height)
+	if m.tableMode {
+		// The message column takes whatever width is left.
+		m.refreshViewer()
+	}
+}
+
+// fitWidth truncates a single line to the usable terminal width.
+func (m Model) fitWidth(line string) string {
+	if m.width <= 4 {
+		return line
+	}
+	return lipgloss.NewStyle().MaxWidth(m.width - 4).Render(line)
+}
+
+// handleLogViewKey handles the pager keys of the log viewer. It returns nil
+// for keys the viewer doesn't use so they fall through to the global ones.
+func (m Model) handleLogViewKey(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	if m.detailOpen {
+		return m.handleDetailKey(msg)
+	}
+
+	switch msg.String() {
+	case "down", "j":
+		m.moveEntryCursor(1)
+	case "up", "k":
+		m.moveEntryCursor(-1)
+	case "ctrl+e":
+		m.viewer.ScrollDown(1)
+		m.keepCursorVisible()
+	case "ctrl+y":
+		m.viewer.ScrollUp(1)
+		m.keepCursorVisible()
+	case "pgdown", " ", "ctrl+f":
+		m.viewer.PageDown()
+		m.keepCursorVisible()
+	case "pgup", "b", "ctrl+b":
+		m.viewer.PageUp()
+		m.keepCursorVisible()
+	case "ctrl+d":
+		m.viewer.HalfPageDown()
+		m.keepCursorVisible()
+	case "ctrl+u":
+		m.viewer.HalfPageUp()
+		m.keepCursorVisible()
+	case "g", "home":
+		if m.timeline != nil {
+			m, cmd := m.loadTimelineAt(0, m.timeline.Days()[0])
+			return &m, cmd
+		}
+		m.viewer.GotoTop()
+		m.entryCursor = 0
+	case "G", "end":
+		if m.timeline != nil {
+			m, cmd := m.loadTimelineAt(len(m.timeline.Days())-1, time.Time{})
+			return &m, cmd
+		}
+		m.viewer.GotoBottom()
+		m.entryCursor = max(0, len(m.viewEntries)-1)
+	case "enter":
+		m, cmd := m.openDetail()
+		return &m, cmd
+	case "/":
+		m, cmd := m.startSearch(false)
+		return &m, cmd
+	case "?":
+		m, cmd := m.startSearch(true)
+		return &m, cmd
+	case "1", "2", "3", "4":
+		m, cmd := m.toggleLevel(levelToggleKeys[msg.String()])
+		return &m, cmd
+	case "f":
+		m, cmd := m.toggleFollow()
+		return &m, cmd
+	case "t":
+		m, cmd := m.toggleTable()
+		return &m, cmd
+	case "c":
+		m, cmd := m.startColumns()
+		return &m, cmd
+	case "s":
+		m, cmd := m.cycleSort(false)
+		return &m, cmd
+	case "S":
+		m, cmd := m.cycleSort(true)
+		return &m, cmd
+	case "&":
+		m, cmd := m.startFilter()
+		return &m, cmd
+	case ":":
+		m, cmd := m.startQuery()
+		return &m, cmd
+	case "@":
+		m, cmd := m.startJump()
+		return &m, cmd
+	case "r":
+		m, cmd := m.startTrace()
+		return &m, cmd
+	case "esc":
+		if m.activeQuery != nil {
+			m, cmd := m.closeQuery()
+			return &m, cmd
+		}
+		if m.activeGroup != nil {
+			m, cmd := m.closeGroup()
+			return &m, cmd
+		}
+		return nil, nil
+	case "n":
+		m, cmd := m.nextMatch(false)
+		return &m, cmd
+	case "N":
+		m, cmd := m.nextMatch(true)
+		return &m, cmd
+	default:
+		return nil, nil
+	}
+	m.message = ""
+	m.extendTimeline()
+	return &m, nil
+}
+
+func (m Model) renderLogView() string {
+	if m.detailOpen {
+		return m.renderDetail()
+	}
+
+	title := fmt.Sprintf("📋 Viewing: %s", filepath.Base(m.viewingFile))
+	if m.timeline != nil {
+		title = m.timelineTitle()
+	}
+	if m.activeGroup != nil {
+		title = m.groupTitle()
+	}
+	if m.activeQuery != nil {
+		files := "files"
+		if m.queryFiles == 1 {
+			files = "file"
+		}
+		title = fmt.Sprintf("🔎 %s: %s  (%d matches in %d %s)", m.viewerProject(), m.activeQuery, len(m.allEntries), m.queryFiles, files)
+		if m.activeTrace != nil {
+			title = m.traceTitle()
+		}
+	}
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme().Accent).
+		Render(title)
+	if filters := m.filterSummary(); filters != "" {
+		header += "  " + lipgloss.NewStyle().
+			Foreground(m.theme().Message).
+			Render(filters)
+	}
+	if m.following {
+		header += "  " + lipgloss.NewStyle().
+			Bold(true).
+			Foreground(m.theme().levelColor("INFO")).
+			Render("● following")
+	}
+	header = m.fitWidth(header)
+
+	first, last, total, percent := m.viewer.Position()
+	position := fmt.Sprintf("lines %d-%d of %d (%d%%)", first, last, total, percent)
+
+	footer := position + "  •  j/k move  ENTER details  PgUp/PgDn ^u/^d g/G scroll  / ? search  n/N next  1-4 levels  & filter  t table  f follow  : query  @ jump  r trace  ESC back"
+	if m.tableMode {
+		footer = position + "  •  j/k move  ENTER details  / ? search  n/N next  1-4 levels  & filter  c columns  s/S sort  t list  f follow  : query  @ jump  r trace  ESC back"
+	}
+	if m.querying {
+		footer = position + "  •  " + m.queryStatus()
+	} else if m.jumping {
+		footer = position + "  •  " + m.jumpStatus()
+	} else if m.editingColumns {
+		footer = position + "  •  columns: " + m.editValue + "█  •  data keys, optionally key:width, ENTER save, ESC cancel"
+	} else if m.filtering {
+		footer = position + "  •  &" + m.editValue + "█  •  key=value or key!=value terms, ENTER apply (empty clears), ESC cancel"
+	} else if status := m.searchStatus(); status != "" {
+		footer = position + "  •  " + status
+	}
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme().Muted).
+		Render(m.fitWidth(footer))
+
+	cursor := lipgloss.NewStyle().
+		Foreground(m.theme().Accent).
+		Bold(true).
+		Render("> ")
+	visible, items := m.viewer.VisibleLines()
+	lines := make([]string, len(visible))
+	for i, line := range visible {
+		gutter := "  "
+		if items[i] == m.entryCursor && (i == 0 || items[i-1] != items[i]) {
+			gutter = cursor
+		}
+		lines[i] = gutter + line
+	}
+	for len(lines) < m.viewer.visibleHeight() {
+		lines = append(lines, "")
+	}
+	if m.tableMode {
+		lines = append([]string{"  " + m.renderTableHeader()}, lines...)
+	}
+	content := lipgloss.NewStyle().
+		Foreground(m.theme().Text).
+		Render(strings.Join(lines, "\n"))
+
+	// The message line is always there so the layout doesn't jump.
+	messageStr := "\n" + lipgloss.NewStyle().
+		Foreground(m.theme().Message).
+		Render(m.fitWidth(m.message))
+
+	return fmt.Sprintf("%s\n\n%s\n\n%s%s", header, content, instructions, messageStr)
+}
@@ -2,15 +2,17 @@ package tui
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"os"
-	"os/user"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/LFroesch/logdog/internal/detector"
+	"github.com/LFroesch/logdog/internal/logs"
+	"github.com/LFroesch/logdog/internal/query"
+	"github.com/LFroesch/logdog/internal/stats"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -20,10 +22,16 @@ type screen int
 const (
 	screenMain screen = iota
 	screenInstall
+	screenInstallPlan
+	screenUninstall
 	screenLogs
 	screenLogView
 	screenSettings
 	screenGlobalProjects
+	screenStats
+	screenGroups
+	screenSchema
+	screenNumbers
 )
 
 type Model struct {
@@ -31,27 +39,152 @@ type Model struct {
 	projectPath      string
 	language         detector.Language
 	config           detector.Config
+	global           detector.GlobalConfig
 	logFiles         []string
 	cursor           int
 	message          string
 	confirmingDelete bool
 	confirmingClear  bool
-	deleteFileIndex  int
-	logContent       string
-	// Global project selection
-	globalProjects    []string
+	// What confirming the clear removes
+	retentionPlan   *detector.RetentionPlan
+	deleteFileIndex int
+	// Terminal size from the last tea.WindowSizeMsg
+	width  int
+	height int
+	// Install options
+	dryRun      bool
+	installPlan string
+	// Inline text editing of form fields
+	editing   bool
+	editValue string
+	// Uninstall options
+	confirmingUninstall bool
+	uninstallRewrite    bool
+	uninstallLogDir     logDirAction
+	// Log viewer
+	viewer      viewport
+	viewingFile string
+	viewEntries []logs.Entry
+	// entryCursor is the selected index into viewEntries; detail shows that
+	// entry in full while detailOpen is set.
+	entryCursor int
+	detailOpen  bool
+	detail      viewport
+	// viewSources are the project log directories the viewer shows entries
+	// from; more than one for a cross-project timeline.
+	viewSources []logs.Source
+	// Query results: activeQuery is nil when viewing a single file.
+	querying    bool
+	activeQuery *query.Query
+	queryFiles  int
+	queryReturn string
+	// activeTrace is set when the query results are a trace, the entries
+	// sharing one correlation id.
+	activeTrace *trace
+	// Timeline: the days timelineFirst..timelineLast of timeline are loaded.
+	timeline      *logs.Timeline
+	timelineFirst int
+	timelineLast  int
+	jumping       bool
+	// Table view: data keys shown as columns, and the sort column (a data
+	// key, or one of the sort* constants for the built-in columns).
+	tableMode      bool
+	tableColumns   []tableColumn
+	sortColumn     string
+	sortDesc       bool
+	editingColumns bool
+	// Log viewer filters. allEntries is everything read from the file;
+	// viewEntries are the ones passing the filters, and viewSource maps each
+	// back to its index in allEntries.
+	allEntries   []logs.Entry
+	viewSource   []int
+	hiddenLevels map[string]bool
+	fieldFilters []fieldFilter
+	filtering    bool
+	// Log viewer search
+	searching      bool
+	searchQuery    string
+	searchRegex    bool
+	searchBackward bool
+	searchRe       *regexp.Regexp
+	searchErr      error
+	searchMatches  []int
+	searchCurrent  int
+	searchOrigin   int
+	// Follow mode: followOffset is how far into viewingFile has been read,
+	// followDay the day whose file is being followed.
+	following    bool
+	followID     int
+	followOffset int64
+	followDay    time.Time
+	// Stats screen: statsReport is nil until the files have been read;
+	// statsDone of statsTotal bytes are read so far.
+	statsID     int
+	statsReport *stats.Report
+	statsDone   int64
+	statsTotal  int64
+	statsView   viewport
+	// Error groups: groups are the report's, sorted once when it arrives;
+	// groupCursor is the selected one, activeGroup the one whose entries
+	// the viewer shows.
+	groups      []*stats.Group
+	groupCursor int
+	activeGroup *stats.Group
+	// Numeric analytics: numbers is the field being analyzed in detail,
+	// nil for the summary of every numeric field.
+	numbers        *stats.Numbers
+	numbersPending bool
+	analyzing      bool
+	// projectAnomalies are the anomalies found in each global project's
+	// recent logs.
+	projectAnomalies map[string][]stats.Anomaly
+	// Last visits: state is remembered between runs, and projectChanges is
+	// what each visited project logged since.
+	state          detector.State
+	projectChanges map[string]stats.Changes
+	// Global project selection, sorted by projectSort once the overviews
+	// are read.
+	globalProjects   []string
+	projectOverviews map[string]projectOverview
+	projectSort      projectSort
+	projectSortDesc  bool
+	// Managing a global project: confirmingProject is the removal waiting
+	// for 'y', or logDirKeep.
+	confirmingProject logDirAction
+	renamingProject   bool
+	mergingProject    bool
 	selectedProject   string
+	markedProjects    map[string]bool
 	// Settings
-	retentionDays     int
+	settingsGlobal bool
+}
+
+// logDirAction is what uninstall does with ~/logdog/<project>.
+type logDirAction int
+
+const (
+	logDirKeep logDirAction = iota
+	logDirArchive
+	logDirDelete
+)
+
+func (a logDirAction) String() string {
+	switch a {
+	case logDirArchive:
+		return "archive to tarball"
+	case logDirDelete:
+		return "delete"
+	default:
+		return "keep"
+	}
 }
 
 func scanGlobalProjects() []string {
-	usr, err := user.Current()
+	logdogDir, err := detector.LogRoot()
 	if err != nil {
 		return []string{}
 	}
 
-	logdogDir := filepath.Join(usr.HomeDir, "logdog")
 	var projects []string
 
 	entries, err := os.ReadDir(logdogDir)
@@ -77,117 +210,268 @@ func NewModel() Model {
 		logFiles = lang.GetLogPaths(wd)
 	}
 
+	var messages []string
+	global, err := detector.LoadGlobalConfig()
+	if err != nil {
+		messages = append(messages, fmt.Sprintf("❌ %v (using defaults)", err))
+	}
+	config, err := detector.LoadProjectConfig(wd)
+	if err != nil {
+		messages = append(messages, fmt.Sprintf("❌ %v (using defaults)", err))
+	}
+	state, err := detector.LoadState()
+	if err != nil {
+		messages = append(messages, fmt.Sprintf("❌ %v", err))
+	} else if lang != nil {
+		// Remember where this project's logs come from for the global view.
+		if logDir, err := detector.ResolveLogDir(wd, config); err == nil && state.RememberSource(logDir, wd) {
+			if err := detector.SaveState(state); err != nil {
+				messages = append(messages, fmt.Sprintf("❌ %v", err))
+			}
+		}
+	}
+
 	return Model{
-		screen:      screenMain,
-		projectPath: wd,
-		language:    lang,
-		config: detector.Config{
-			LogLevel:   "INFO",
-			OutputDir:  "logdog/logs",
-			MaxFiles:   30,
-			DateFormat: "2006-01-02",
-		},
-		logFiles:         logFiles,
-		globalProjects:   scanGlobalProjects(),
-		retentionDays:    7,
+		screen:         screenMain,
+		projectPath:    wd,
+		language:       lang,
+		config:         config,
+		global:         global,
+		state:          state,
+		logFiles:       logFiles,
+		message:        strings.Join(messages, "\n"),
+		globalProjects: scanGlobalProjects(),
+		settingsGlobal: lang == nil,
+		hiddenLevels:   hiddenLevelsBelow(global.ViewerLevel),
 	}
 }
 
+// saveProjectConfig writes the current config back to the project's
+// .logdog.json so the rest of the team picks it up. Outside a detected
+// project there is nowhere to save it.
+func (m Model) saveProjectConfig() error {
+	if m.language == nil {
+		return nil
+	}
+	return detector.SaveProjectConfig(m.projectPath, m.config)
+}
+
+func (m Model) confirming() bool {
+	return m.confirmingDelete || m.confirmingClear || m.confirmingUninstall || m.confirmingProject != logDirKeep
+}
+
 func (m Model) Init() tea.Cmd {
-	return nil
+	return scanChanges(m.state)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.resizeViewer()
+		m.resizeStats()
+	case followTickMsg:
+		return m.pollFollow(msg)
+	case queryDoneMsg:
+		return m.showQueryResults(msg)
+	case statsProgressMsg:
+		return m.statsProgress(msg)
+	case statsDoneMsg:
+		return m.showStats(msg)
+	case groupEntriesMsg:
+		return m.showGroup(msg)
+	case numbersDoneMsg:
+		return m.showNumbers(msg)
+	case anomaliesMsg:
+		return m.showAnomalies(msg)
+	case changesMsg:
+		return m.showChanges(msg)
+	case overviewMsg:
+		return m.showOverview(msg)
 	case tea.KeyMsg:
+		if m.editing {
+			return m.handleEditKey(msg)
+		}
+		if m.screen == screenLogView && !m.confirming() {
+			if handled, cmd := m.handleLogViewKey(msg); handled != nil {
+				return *handled, cmd
+			}
+		}
+		if m.screen == screenStats || m.screen == screenSchema || m.screen == screenNumbers {
+			if handled, cmd := m.handleStatsKey(msg); handled != nil {
+				return *handled, cmd
+			}
+		}
+		if m.screen == screenGroups {
+			if handled, cmd := m.handleGroupsKey(msg); handled != nil {
+				return *handled, cmd
+			}
+		}
+		// A pending confirmation is cancelled by any key but 'y', including
+		// the ones other screens use.
+		if m.confirming() && msg.String() != "y" {
+			m.confirmingDelete = false
+			m.confirmingClear = false
+			m.confirmingUninstall = false
+			m.confirmingProject = logDirKeep
+			m.message = ""
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
+			if m.screen == screenLogView {
+				m.rememberVisit()
+			}
 			return m, tea.Quit
 		case "up", "k":
-			if !m.confirmingDelete && !m.confirmingClear {
+			if !m.confirming() {
 				if m.cursor > 0 {
 					m.cursor--
 				}
 				m.message = ""
 			}
 		case "down", "j":
-			if !m.confirmingDelete && !m.confirmingClear {
+			if !m.confirming() {
 				if m.cursor < m.getMaxCursor() {
 					m.cursor++
 				}
 				m.message = ""
 			}
 		case "enter":
-			if !m.confirmingDelete && !m.confirmingClear {
-				if m.screen == screenInstall {
-					if m.language != nil {
-						err := m.language.Install(m.projectPath, m.config)
-						if err != nil {
-							m.message = fmt.Sprintf("❌ Error: %v", err)
-						} else {
-							m.message = "✅ Logger installed successfully! Check internal/logdog/logger.go"
-							m.logFiles = m.language.GetLogPaths(m.projectPath)
-						}
-					} else {
-						m.message = "❌ No supported language detected"
-					}
-					m.screen = screenMain
-					m.cursor = 0
-					return m, tea.ClearScreen
+			if !m.confirming() {
+				if m.screen == screenInstall && m.dryRun {
+					return m.handleDryRun()
+				} else if m.screen == screenInstall || m.screen == screenInstallPlan {
+					return m.handleInstall()
 				} else {
 					return m.handleEnter()
 				}
 			}
 		case "v":
-			if m.screen == screenLogs && len(m.logFiles) > 0 && !m.confirmingDelete && !m.confirmingClear {
+			if m.screen == screenLogs && len(m.logFiles) > 0 && !m.confirming() {
 				return m.handleViewLog()
 			}
+		case "e":
+			if m.screen == screenLogs && len(m.logFiles) > 0 && !m.confirming() {
+				return m.openStats(screenGroups)
+			}
+		case "n":
+			if m.screen == screenLogs && len(m.logFiles) > 0 && !m.confirming() {
+				return m.openStats(screenNumbers)
+			}
+		case "F":
+			if m.screen == screenLogs && len(m.logFiles) > 0 && !m.confirming() {
+				return m.openStats(screenSchema)
+			}
 		case "d":
-			if m.screen == screenLogs && len(m.logFiles) > 0 && !m.confirmingDelete && !m.confirmingClear {
+			if m.screen == screenLogs && len(m.logFiles) > 0 && !m.confirming() {
 				return m.handleDeleteLog()
+			} else if m.screen == screenGlobalProjects && !m.confirming() {
+				return m.handleProjectRemoval(logDirDelete)
+			} else if m.screen == screenInstall {
+				m.dryRun = !m.dryRun
+				m.message = ""
 			}
 		case "c":
-			if m.screen == screenLogs && len(m.logFiles) > 0 && !m.confirmingDelete && !m.confirmingClear {
+			if m.screen == screenLogs && len(m.logFiles) > 0 && !m.confirming() {
 				return m.handleClearOldLogs()
 			}
+		case "s":
+			if m.screen == screenLogs && len(m.logFiles) > 0 && !m.confirming() {
+				return m.openStats(screenStats)
+			} else if m.screen == screenGlobalProjects {
+				return m.cycleProjectSort(false)
+			}
+		case "S":
+			if m.screen == screenGlobalProjects {
+				return m.cycleProjectSort(true)
+			}
+		case "a":
+			if m.screen == screenGlobalProjects && !m.confirming() {
+				return m.handleProjectRemoval(logDirArchive)
+			}
+		case "r":
+			if m.screen == screenGlobalProjects && !m.confirming() {
+				return m.startProjectRename()
+			}
+		case "m":
+			if m.screen == screenGlobalProjects && !m.confirming() {
+				return m.startProjectMerge()
+			}
+		case ":":
+			if m.screen == screenLogs && len(m.logFiles) > 0 && !m.confirming() {
+				return m.startQuery()
+			}
+		case "t":
+			if m.screen == screenLogs && len(m.logFiles) > 0 && !m.confirming() {
+				return m.openTimeline(fileSource(m.logFiles[m.cursor]))
+			} else if m.screen == screenGlobalProjects {
+				return m.openMergedTimeline()
+			}
 		case "y":
 			if m.confirmingDelete {
 				return m.confirmDelete()
 			} else if m.confirmingClear {
 				return m.confirmClearOldLogs()
+			} else if m.confirmingUninstall {
+				return m.confirmUninstall()
+			} else if m.confirmingProject != logDirKeep {
+				return m.confirmProjectRemoval()
+			}
+		case " ":
+			if m.screen == screenUninstall && !m.confirming() {
+				return m.toggleUninstallOption()
+			} else if m.screen == screenInstall {
+				return m.handleInstallField()
+			} else if m.screen == screenSettings {
+				return m.handleSettingsField()
+			} else if m.screen == screenGlobalProjects {
+				return m.toggleProjectMark()
+			}
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			if m.screen == screenMain {
+				return m.openChanges(int(msg.String()[0] - '1'))
 			}
 		case "+", "=":
-			if m.screen == screenSettings && !m.confirmingDelete && !m.confirmingClear {
-				if m.retentionDays < 365 {
-					m.retentionDays++
-					m.message = fmt.Sprintf("Retention set to %d days", m.retentionDays)
-				}
+			if m.screen == screenSettings && !m.confirming() {
+				return m.adjustSetting(1)
 			}
 		case "-", "_":
-			if m.screen == screenSettings && !m.confirmingDelete && !m.confirmingClear {
-				if m.retentionDays > 1 {
-					m.retentionDays--
-					m.message = fmt.Sprintf("Retention set to %d days", m.retentionDays)
-				}
+			if m.screen == screenSettings && !m.confirming() {
+				return m.adjustSetting(-1)
 			}
 		case "esc":
-			if m.confirmingDelete || m.confirmingClear {
+			if m.confirming() {
 				m.confirmingDelete = false
 				m.confirmingClear = false
+				m.confirmingUninstall = false
+				m.confirmingProject = logDirKeep
 				m.message = ""
 			} else {
+				if m.screen == screenLogView {
+					m.rememberVisit()
+				}
 				m.screen = screenMain
 				m.cursor = 0
 				m.message = ""
 				m.confirmingDelete = false
 				m.confirmingClear = false
-				m.logContent = ""
+				m.confirmingUninstall = false
+				m.confirmingProject = logDirKeep
+				m.viewer = viewport{}
+				m.viewingFile = ""
+				m.following = false
+				m.installPlan = ""
 				m.selectedProject = ""
 			}
 		default:
-			if m.confirmingDelete || m.confirmingClear {
+			if m.confirming() {
 				m.confirmingDelete = false
 				m.confirmingClear = false
+				m.confirmingUninstall = false
+				m.confirmingProject = logDirKeep
 				m.message = ""
 			} else {
 				m.message = ""
@@ -198,47 +482,60 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleClearOldLogs() (Model, tea.Cmd) {
-	// Count logs older than retentionDays
-	cutoffDate := time.Now().AddDate(0, 0, -m.retentionDays)
-	var oldLogs []string
-
-	for _, logFile := range m.logFiles {
-		if info, err := os.Stat(logFile); err == nil {
-			if info.ModTime().Before(cutoffDate) {
-				oldLogs = append(oldLogs, logFile)
-			}
-		}
+	// Find entries older than their level's retention period, and files
+	// beyond the newest max_files
+	config := m.retentionConfig()
+	plan, err := detector.PlanRetention(m.logFiles, config.Retention, config.MaxFiles, time.Now())
+	if err != nil {
+		m.message = fmt.Sprintf("❌ %v", err)
+		return m, nil
 	}
 
-	if len(oldLogs) == 0 {
-		m.message = fmt.Sprintf("No log files older than %d days found", m.retentionDays)
+	rule := fmt.Sprintf("older than %s", config.Retention)
+	if config.MaxFiles > 0 {
+		rule += fmt.Sprintf(", or beyond the newest %d files", config.MaxFiles)
+	}
+	if len(plan.Files) == 0 {
+		m.message = fmt.Sprintf("No entries %s found", rule)
 		return m, nil
 	}
 
-	m.message = fmt.Sprintf("Clear %d log files older than %d days? Press 'y' to confirm, any other key to cancel", len(oldLogs), m.retentionDays)
+	m.message = fmt.Sprintf("Remove %s %s? Press 'y' to confirm, any other key to cancel", plan.Summary(), rule)
+	m.retentionPlan = plan
 	m.confirmingClear = true
 	return m, nil
 }
 
-func (m Model) confirmClearOldLogs() (Model, tea.Cmd) {
-	cutoffDate := time.Now().AddDate(0, 0, -m.retentionDays)
-	var deletedCount int
-	var errors []string
-
-	for _, logFile := range m.logFiles {
-		if info, err := os.Stat(logFile); err == nil {
-			if info.ModTime().Before(cutoffDate) {
-				if err := os.Remove(logFile); err != nil {
-					errors = append(errors, fmt.Sprintf("Failed to delete %s: %v", filepath.Base(logFile), err))
-				} else {
-					deletedCount++
-				}
-			}
+// retentionConfig is the config of the project whose logs are listed, for
+// its retention and max files: a global project's comes from its source's
+// .logdog.json when logdog knows where that is.
+func (m Model) retentionConfig() detector.Config {
+	if m.selectedProject == "" {
+		return m.config
+	}
+	if source := m.state.Projects[m.selectedProject].Source; source != "" {
+		if config, err := detector.LoadProjectConfig(source); err == nil {
+			return config
 		}
 	}
+	return m.global.Defaults
+}
+
+func (m Model) confirmClearOldLogs() (Model, tea.Cmd) {
+	plan := m.retentionPlan
+	m.retentionPlan = nil
+	m.confirmingClear = false
+	if plan == nil {
+		return m, nil
+	}
+
+	total, _ := plan.Removed()
+	err := plan.Apply()
 
 	// Refresh log files list
-	if m.language != nil {
+	if m.selectedProject != "" {
+		m.logFiles = m.getLogFilesForProject(m.selectedProject)
+	} else if m.language != nil {
 		m.logFiles = m.language.GetLogPaths(m.projectPath)
 	}
 
@@ -249,123 +546,14 @@ func (m Model) confirmClearOldLogs() (Model, tea.Cmd) {
 		m.cursor = 0
 	}
 
-	if len(errors) > 0 {
-		m.message = fmt.Sprintf("✅ Deleted %d files. ❌ Errors: %s", deletedCount, strings.Join(errors, "; "))
-	} else {
-		m.message = fmt.Sprintf("✅ Cleared %d old log files", deletedCount)
-	}
-
-	m.confirmingClear = false
-	return m, nil
-}
-
-func (m Model) handleViewLog() (Model, tea.Cmd) {
-	if m.cursor < len(m.logFiles) {
-		return m.viewLogContent()
-	}
-	return m, nil
-}
-
-func (m Model) viewLogContent() (Model, tea.Cmd) {
-	filePath := m.logFiles[m.cursor]
-
-	file, err := os.Open(filePath)
 	if err != nil {
-		m.message = fmt.Sprintf("❌ Error reading log: %v", err)
-		return m, nil
-	}
-	defer file.Close()
-
-	var formattedLogs strings.Builder
-	scanner := bufio.NewScanner(file)
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
-
-		var logEntry map[string]any
-		if err := json.Unmarshal([]byte(line), &logEntry); err == nil {
-			formattedLogs.WriteString(m.formatLogEntry(logEntry))
-			formattedLogs.WriteString("\n")
-		} else {
-			formattedLogs.WriteString(line)
-			formattedLogs.WriteString("\n")
-		}
+		m.message = fmt.Sprintf("❌ %v", err)
+	} else {
+		m.message = fmt.Sprintf("✅ Removed %d expired entries from %d files (%d deleted)", total, len(plan.Files), plan.Emptied())
 	}
-
-	m.screen = screenLogView
-	m.logContent = formattedLogs.String()
-	m.cursor = 0
-
 	return m, nil
 }
 
-func (m Model) formatLogEntry(entry map[string]any) string {
-	timestamp, _ := entry["timestamp"].(string)
-	level, _ := entry["level"].(string)
-	message, _ := entry["message"].(string)
-	data, _ := entry["data"].(map[string]any)
-
-	var result strings.Builder
-
-	if timestamp != "" {
-		if t, err := time.Parse(time.RFC3339, timestamp); err == nil {
-			humanTime := t.Format("Jan 02 15:04:05")
-			result.WriteString(lipgloss.NewStyle().
-				Foreground(lipgloss.Color("240")).
-				Render(humanTime))
-		} else {
-			result.WriteString(lipgloss.NewStyle().
-				Foreground(lipgloss.Color("240")).
-				Render(timestamp))
-		}
-		result.WriteString(" ")
-	}
-
-	if level != "" {
-		var levelColor lipgloss.Color
-		switch level {
-		case "ERROR":
-			levelColor = lipgloss.Color("196")
-		case "WARN":
-			levelColor = lipgloss.Color("208")
-		case "INFO":
-			levelColor = lipgloss.Color("46")
-		case "DEBUG":
-			levelColor = lipgloss.Color("240")
-		default:
-			levelColor = lipgloss.Color("252")
-		}
-
-		result.WriteString(lipgloss.NewStyle().
-			Foreground(levelColor).
-			Bold(true).
-			Render(fmt.Sprintf("[%s]", level)))
-		result.WriteString(" ")
-	}
-
-	if message != "" {
-		result.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252")).
-			Render(message))
-	}
-
-	if len(data) > 0 {
-		result.WriteString(" ")
-		var pairs []string
-		for k, v := range data {
-			pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
-		}
-		result.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("99")).
-			Render(fmt.Sprintf("{%s}", strings.Join(pairs, ", "))))
-	}
-
-	return result.String()
-}
-
 func (m Model) handleDeleteLog() (Model, tea.Cmd) {
 	if m.cursor < len(m.logFiles) {
 		filename := filepath.Base(m.logFiles[m.cursor])
@@ -405,6 +593,10 @@ func (m Model) View() string {
 		s = m.renderMain()
 	case screenInstall:
 		s = m.renderInstall()
+	case screenInstallPlan:
+		s = m.renderInstallPlan()
+	case screenUninstall:
+		s = m.renderUninstall()
 	case screenLogs:
 		s = m.renderLogs()
 	case screenLogView:
@@ -413,6 +605,14 @@ func (m Model) View() string {
 		s = m.renderSettings()
 	case screenGlobalProjects:
 		s = m.renderGlobalProjects()
+	case screenStats:
+		s = m.renderStats()
+	case screenGroups:
+		s = m.renderGroups()
+	case screenSchema:
+		s = m.renderSchema()
+	case screenNumbers:
+		s = m.renderNumbers()
 	default:
 		s = m.renderMain()
 	}
@@ -422,32 +622,10 @@ func (m Model) View() string {
 		Render(s)
 }
 
-func (m Model) renderLogView() string {
-	filename := ""
-	if m.deleteFileIndex < len(m.logFiles) {
-		filename = filepath.Base(m.logFiles[m.deleteFileIndex])
-	}
-
-	header := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("99")).
-		Render(fmt.Sprintf("📋 Viewing: %s", filename))
-
-	instructions := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Render("Press ESC to go back")
-
-	content := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252")).
-		Render(m.logContent)
-
-	return fmt.Sprintf("%s\n\n%s\n\n%s", header, content, instructions)
-}
-
 func (m Model) renderMain() string {
 	title := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("99")).
+		Foreground(m.theme().Accent).
 		Render("🐕 Logdog")
 
 	var status string
@@ -462,6 +640,7 @@ func (m Model) renderMain() string {
 
 	options := []string{
 		"📦 Install/Setup Logger",
+		"🧹 Uninstall Logger",
 		"📋 View Logs",
 		"🌐 View All Logs (Global)",
 		"⚙️  Settings",
@@ -480,21 +659,15 @@ func (m Model) renderMain() string {
 	messageStr := ""
 	if m.message != "" {
 		messageStr = "\n" + lipgloss.NewStyle().
-			Foreground(lipgloss.Color("226")).
+			Foreground(m.theme().Message).
 			Render(m.message)
 	}
 
-	return fmt.Sprintf("%s\n\n%s\n\n%s%s", title, status, optionsStr, messageStr)
-}
-
-func (m Model) renderInstall() string {
-	if m.language == nil {
-		return "No supported language detected. Press ESC to go back."
+	if changes := m.renderChanges(); changes != "" {
+		optionsStr += "\n" + changes + "\n"
 	}
 
-	status := fmt.Sprintf("Installing logger for %s project...\n\nThis will create:\n- internal/logdog/logger.go\n- logdog/logs/ directory\n\nPress ENTER to install or ESC to cancel", m.language.Name())
-
-	return status
+	return fmt.Sprintf("%s\n\n%s\n\n%s%s", title, status, optionsStr, messageStr)
 }
 
 func (m Model) getLogEntryCount(filepath string) int {
@@ -528,15 +701,15 @@ func (m Model) renderLogs() string {
 
 	header := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("99")).
+		Foreground(m.theme().Accent).
 		Render(headerText)
 
 	selectedStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("57")).
-		Foreground(lipgloss.Color("230"))
+		Background(m.theme().SelectedBg).
+		Foreground(m.theme().SelectedFg)
 
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(m.theme().Text)
 
 	var rows []string
 	for i, file := range m.logFiles {
@@ -554,39 +727,22 @@ func (m Model) renderLogs() string {
 	}
 
 	instructions := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Render("\nPress 'v' to view, 'd' to delete, 'c' to clear old logs, ESC to go back")
-
-	messageStr := ""
-	if m.message != "" {
-		messageStr = "\n\n" + lipgloss.NewStyle().
-			Foreground(lipgloss.Color("226")).
-			Render(m.message)
+		Foreground(m.theme().Muted).
+		Render("\nPress 'v' to view, 't' for the timeline of all files, ':' to query them, 's' for stats, 'e' for error groups, 'F' for the fields used, 'n' for numeric fields, 'd' to delete, 'c' to clear old logs, ESC to go back")
+	if m.querying {
+		instructions = lipgloss.NewStyle().
+			Foreground(m.theme().Muted).
+			Render("\n" + m.queryStatus())
 	}
 
-	return fmt.Sprintf("%s\n\n%s%s%s", header, strings.Join(rows, "\n"), instructions, messageStr)
-}
-
-func (m Model) renderSettings() string {
-	header := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("99")).
-		Render("⚙️ Settings")
-
-	settingsText := fmt.Sprintf("Log Retention: %d days\n\nUse +/- to adjust retention days", m.retentionDays)
-
-	instructions := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Render("\nPress +/- to adjust, ESC to go back")
-
 	messageStr := ""
 	if m.message != "" {
 		messageStr = "\n\n" + lipgloss.NewStyle().
-			Foreground(lipgloss.Color("226")).
+			Foreground(m.theme().Message).
 			Render(m.message)
 	}
 
-	return fmt.Sprintf("%s\n\n%s%s%s", header, settingsText, instructions, messageStr)
+	return fmt.Sprintf("%s\n\n%s%s%s", header, strings.Join(rows, "\n"), instructions, messageStr)
 }
 
 func (m Model) renderGlobalProjects() string {
@@ -596,35 +752,49 @@ func (m Model) renderGlobalProjects() string {
 
 	header := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("99")).
+		Foreground(m.theme().Accent).
 		Render("🌐 Select Project")
 
 	selectedStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("57")).
-		Foreground(lipgloss.Color("230"))
+		Background(m.theme().SelectedBg).
+		Foreground(m.theme().SelectedFg)
 
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(m.theme().Text)
 
-	var rows []string
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(m.theme().Muted)
+
+	rows := []string{m.fitWidth(lipgloss.NewStyle().Bold(true).Foreground(m.theme().Accent).Render("      " + m.overviewHeader()))}
 	for i, project := range m.globalProjects {
-		row := project
+		mark := "[ ]"
+		if m.markedProjects[project] {
+			mark = "[x]"
+		}
+		row := mark + " " + m.overviewRow(project)
 		if i == m.cursor {
 			row = selectedStyle.Render("> " + row)
 		} else {
 			row = normalStyle.Render("  " + row)
 		}
-		rows = append(rows, row)
+		rows = append(rows, m.fitWidth(row))
+		if badge := m.anomalyBadge(project); badge != "" {
+			rows = append(rows, "      "+badge)
+		}
 	}
+	rows = append(rows, "", mutedStyle.Render(m.overviewFooter()))
 
-	instructions := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Render("\nPress ENTER to view logs, ESC to go back")
+	instructions := mutedStyle.
+		Render("\nPress ENTER to view logs, SPACE to mark projects, 't' for a merged timeline of the marked ones, 's'/'S' to sort, ESC to go back\n" +
+			"'r' rename, 'm' merge into another project, 'a' archive to a tarball, 'd' delete")
+	if m.editing {
+		instructions = "\n" + m.projectPrompt()
+	}
 
 	messageStr := ""
 	if m.message != "" {
 		messageStr = "\n\n" + lipgloss.NewStyle().
-			Foreground(lipgloss.Color("226")).
+			Foreground(m.theme().Message).
 			Render(m.message)
 	}
 
@@ -632,12 +802,12 @@ func (m Model) renderGlobalProjects() string {
 }
 
 func (m Model) getLogFilesForProject(projectName string) []string {
-	usr, err := user.Current()
+	logRoot, err := detector.LogRoot()
 	if err != nil {
 		return []string{}
 	}
 
-	logsDir := filepath.Join(usr.HomeDir, "logdog", projectName)
+	logsDir := filepath.Join(logRoot, projectName)
 	var paths []string
 
 	filepath.Walk(logsDir, func(path string, info os.FileInfo, err error) error {
@@ -660,16 +830,25 @@ func (m Model) handleEnter() (Model, tea.Cmd) {
 		case 0:
 			m.screen = screenInstall
 		case 1:
-			m.screen = screenLogs
+			m.screen = screenUninstall
 		case 2:
-			m.screen = screenGlobalProjects
+			m.screen = screenLogs
 		case 3:
-			m.screen = screenSettings
+			m.screen = screenGlobalProjects
+			m.cursor = 0
+			m.message = ""
+			return m, tea.Batch(scanAnomalies(m.globalProjects), scanOverviews(m.globalProjects, m.state))
 		case 4:
+			m.screen = screenSettings
+		case 5:
 			return m, tea.Quit
 		}
 		m.cursor = 0
 		m.message = ""
+	case screenUninstall:
+		return m.handleUninstall()
+	case screenSettings:
+		return m.handleSettingsField()
 	case screenGlobalProjects:
 		if m.cursor < len(m.globalProjects) {
 			m.selectedProject = m.globalProjects[m.cursor]
@@ -686,7 +865,13 @@ func (m Model) handleEnter() (Model, tea.Cmd) {
 func (m Model) getMaxCursor() int {
 	switch m.screen {
 	case screenMain:
-		return 4
+		return 5
+	case screenInstall:
+		return installFieldCount - 1
+	case screenUninstall:
+		return 1
+	case screenSettings:
+		return settingCount - 1
 	case screenLogs:
 		return len(m.logFiles) - 1
 	case screenGlobalProjects:
@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LFroesch/logdog/internal/logs"
+	"github.com/LFroesch/logdog/internal/stats"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	histogramBuckets = 10
+	// maxNumberGroups caps how many groups of a breakdown are listed.
+	maxNumberGroups = 100
+	numberWidth     = 9
+)
+
+// numbersDoneMsg carries the distribution of one field, read in the
+// background.
+type numbersDoneMsg struct {
+	id      int
+	numbers *stats.Numbers
+	err     error
+}
+
+// startNumbers opens the prompt for the field to analyze.
+func (m Model) startNumbers() (Model, tea.Cmd) {
+	m.analyzing = true
+	text := ""
+	if m.numbers != nil {
+		text = m.numbers.Field
+		if m.numbers.By != "" {
+			text += " by " + m.numbers.By
+		}
+	} else if fields := m.statsReport.NumericFields(); len(fields) > 0 {
+		text = fields[0].Name
+	}
+	return m.startEdit(text)
+}
+
+func (m Model) commitNumbers(spec string) (Model, tea.Cmd) {
+	m.analyzing = false
+	numbers, err := stats.ParseNumbers(spec)
+	if err != nil {
+		m.message = fmt.Sprintf("❌ %v", err)
+		return m, nil
+	}
+
+	m.statsID++
+	m.statsDone, m.statsTotal = 0, 0
+	m.numbersPending = true
+	id := m.statsID
+	files := append([]string(nil), m.logFiles...)
+	return m, streamStats(id, func(progress func(done, total int64)) tea.Msg {
+		_, err := stats.Walk(files, progress, func(entry logs.Entry) {
+			numbers.Add(entry)
+		})
+		return numbersDoneMsg{id: id, numbers: numbers, err: err}
+	})
+}
+
+func (m Model) showNumbers(msg numbersDoneMsg) (Model, tea.Cmd) {
+	if msg.id != m.statsID || m.screen != screenNumbers {
+		return m, nil
+	}
+	m.numbersPending = false
+	if msg.err != nil {
+		m.message = fmt.Sprintf("❌ Failed to read logs: %v", msg.err)
+		return m, nil
+	}
+	if msg.numbers.All.Count() == 0 {
+		m.message = fmt.Sprintf("No numeric values of %s found", msg.numbers.Field)
+		return m, nil
+	}
+	m.numbers = msg.numbers
+	m.refreshStats()
+	m.statsView.GotoTop()
+	return m, nil
+}
+
+func (m Model) renderNumbers() string {
+	title := "🔢 Numbers: " + m.statsProject()
+	if m.numbers != nil {
+		title += "  " + m.numbers.Field
+		if m.numbers.By != "" {
+			title += " by " + m.numbers.By
+		}
+	}
+	return m.renderReport(title)
+}
+
+// formatNumbers lists every numeric field, or the detail of the one being
+// analyzed.
+func (m Model) formatNumbers(report *stats.Report) []string {
+	if m.numbers != nil {
+		return m.formatNumbersDetail(m.numbers)
+	}
+
+	text := lipgloss.NewStyle().Foreground(m.theme().Text)
+	muted := lipgloss.NewStyle().Foreground(m.theme().Muted)
+	fields := report.NumericFields()
+	if len(fields) == 0 {
+		return []string{text.Render("No numeric data fields found")}
+	}
+
+	lines := []string{muted.Render(numberRow("KEY", keyColumnWidth(fields), []string{"COUNT", "MIN", "MEAN", "P50", "P95", "P99", "MAX"}))}
+	for _, d := range fields {
+		lines = append(lines, text.Render(numberRow(d.Name, keyColumnWidth(fields), distributionColumns(d))))
+	}
+	lines = append(lines, "", muted.Render("Press 'a' to analyze a field, optionally broken down by another, e.g. duration_ms by path"))
+	return lines
+}
+
+// formatNumbersDetail shows one field's distribution with a histogram and,
+// when grouped, the numbers for each group.
+func (m Model) formatNumbersDetail(numbers *stats.Numbers) []string {
+	heading := lipgloss.NewStyle().Bold(true).Foreground(m.theme().Accent)
+	text := lipgloss.NewStyle().Foreground(m.theme().Text)
+	muted := lipgloss.NewStyle().Foreground(m.theme().Muted)
+	all := numbers.All
+
+	lines := []string{
+		heading.Render(fmt.Sprintf("%s  (%d values)", numbers.Field, all.Count())),
+		muted.Render(numberRow("", 0, []string{"MIN", "MEAN", "P50", "P95", "P99", "MAX"})),
+		text.Render(numberRow("", 0, distributionColumns(all)[1:])),
+		"",
+		heading.Render("Histogram"),
+	}
+
+	histogram := all.Histogram(histogramBuckets)
+	peak, labelWidth := 0, 0
+	labels := make([]string, len(histogram))
+	for i, b := range histogram {
+		peak = max(peak, b.Count)
+		labels[i] = stats.FormatNumber(b.Low) + " – " + stats.FormatNumber(b.High)
+		labelWidth = max(labelWidth, lipgloss.Width(labels[i]))
+	}
+	for i, b := range histogram {
+		lines = append(lines, text.Render(fmt.Sprintf("%s  ", cell(labels[i], labelWidth)))+
+			lipgloss.NewStyle().Foreground(m.theme().Accent).Render(bar(b.Count, peak, statsBarWidth))+
+			muted.Render(fmt.Sprintf(" %d", b.Count)))
+	}
+
+	if numbers.By == "" {
+		return lines
+	}
+	groups := numbers.ByCount()
+	lines = append(lines, "", heading.Render(fmt.Sprintf("By %s  (%d groups)", numbers.By, len(groups))))
+	if len(groups) > maxNumberGroups {
+		groups = groups[:maxNumberGroups]
+	}
+	width := keyColumnWidth(groups)
+	lines = append(lines, muted.Render(numberRow(strings.ToUpper(numbers.By), width, []string{"COUNT", "MIN", "MEAN", "P50", "P95", "P99", "MAX"})))
+	for _, d := range groups {
+		lines = append(lines, text.Render(numberRow(d.Name, width, distributionColumns(d))))
+	}
+	if len(numbers.Groups) > maxNumberGroups {
+		lines = append(lines, muted.Render(fmt.Sprintf("… and %d smaller groups", len(numbers.Groups)-maxNumberGroups)))
+	}
+	return lines
+}
+
+// distributionColumns are the count and headline numbers of a distribution.
+func distributionColumns(d *stats.Distribution) []string {
+	return []string{
+		fmt.Sprint(d.Count()),
+		stats.FormatNumber(d.Min()),
+		stats.FormatNumber(d.Mean()),
+		stats.FormatNumber(d.Percentile(50)),
+		stats.FormatNumber(d.Percentile(95)),
+		stats.FormatNumber(d.Percentile(99)),
+		stats.FormatNumber(d.Max()),
+	}
+}
+
+// keyColumnWidth fits the name column to the distributions' names.
+func keyColumnWidth(ds []*stats.Distribution) int {
+	width := len("KEY")
+	for _, d := range ds {
+		width = max(width, min(schemaKeyWidth, lipgloss.Width(d.Name)))
+	}
+	return width
+}
+
+// numberRow lays out a name followed by right-aligned number columns.
+func numberRow(name string, nameWidth int, columns []string) string {
+	var b strings.Builder
+	if nameWidth > 0 {
+		b.WriteString(cell(name, nameWidth))
+	}
+	for _, c := range columns {
+		b.WriteString(tableGap + fmt.Sprintf("%*s", numberWidth, c))
+	}
+	return strings.TrimPrefix(b.String(), tableGap)
+}
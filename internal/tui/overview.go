@@ -0,0 +1,261 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/detector"
+	"github.com/LFroesch/logdog/internal/stats"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// projectSort is the column the global project list is sorted by.
+type projectSort int
+
+const (
+	sortProjectName projectSort = iota
+	sortProjectSize
+	sortProjectLastEntry
+	sortProjectErrors
+	sortProjectFiles
+	projectSortCount
+)
+
+func (s projectSort) String() string {
+	switch s {
+	case sortProjectSize:
+		return "size"
+	case sortProjectLastEntry:
+		return "last entry"
+	case sortProjectErrors:
+		return "errors today"
+	case sortProjectFiles:
+		return "files"
+	default:
+		return "name"
+	}
+}
+
+// Column widths of the global project list.
+const (
+	overviewNameWidth   = 15
+	overviewFilesWidth  = 7 // "FILES ▼"
+	overviewSizeWidth   = 8
+	overviewDaysWidth   = 15
+	overviewLastWidth   = 12
+	overviewErrorsWidth = 8 // "ERRORS ▼"
+	overviewLoggerWidth = 13
+)
+
+// projectOverview is a global project's row in the list.
+type projectOverview struct {
+	stats.Overview
+	// source is the project directory the logs come from, empty if logdog
+	// has never been run or installed there, and logger the state of its
+	// logger.
+	source string
+	logger string
+}
+
+// overviewMsg carries one project's overview, read in the background.
+type overviewMsg struct {
+	project  string
+	overview projectOverview
+}
+
+// scanOverviews reads every project's overview, one project per command.
+func scanOverviews(projects []string, state detector.State) tea.Cmd {
+	root, err := detector.LogRoot()
+	if err != nil {
+		return nil
+	}
+	now := time.Now()
+	var cmds []tea.Cmd
+	for _, project := range projects {
+		project, source := project, state.Projects[project].Source
+		cmds = append(cmds, func() tea.Msg {
+			// Whatever can't be read is just left blank.
+			overview, _ := stats.ScanOverview(filepath.Join(root, project), now)
+			return overviewMsg{project: project, overview: projectOverview{
+				Overview: overview,
+				source:   source,
+				logger:   loggerState(source),
+			}}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// loggerState describes the logger installed in a project's source.
+func loggerState(source string) string {
+	if source == "" {
+		return "no source"
+	}
+	if _, err := os.Stat(source); err != nil {
+		return "source gone"
+	}
+	lang := detector.DetectLanguage(source)
+	if lang == nil {
+		return "unsupported"
+	}
+	config, _ := detector.LoadProjectConfig(source)
+	status, err := lang.LoggerStatus(source, config)
+	if err != nil {
+		return "error"
+	}
+	return status.String()
+}
+
+func (m Model) showOverview(msg overviewMsg) (Model, tea.Cmd) {
+	if m.projectOverviews == nil {
+		m.projectOverviews = map[string]projectOverview{}
+	}
+	m.projectOverviews[msg.project] = msg.overview
+	if m.projectSort != sortProjectName {
+		m.sortProjects()
+	}
+	return m, nil
+}
+
+// cycleProjectSort moves on to the next sort column, or reverses the order
+// when reverse is set.
+func (m Model) cycleProjectSort(reverse bool) (Model, tea.Cmd) {
+	if reverse {
+		m.projectSortDesc = !m.projectSortDesc
+	} else {
+		m.projectSort = (m.projectSort + 1) % projectSortCount
+		// Names read best A-Z, the numbers biggest or latest first.
+		m.projectSortDesc = m.projectSort != sortProjectName
+	}
+	m.sortProjects()
+	order := "ascending"
+	if m.projectSortDesc {
+		order = "descending"
+	}
+	m.message = fmt.Sprintf("Sorted by %s, %s", m.projectSort, order)
+	return m, nil
+}
+
+// sortProjects orders the global projects by the sort column, keeping the
+// cursor on the same project.
+func (m *Model) sortProjects() {
+	var selected string
+	if m.cursor < len(m.globalProjects) {
+		selected = m.globalProjects[m.cursor]
+	}
+
+	projects := append([]string(nil), m.globalProjects...)
+	sort.SliceStable(projects, func(i, j int) bool {
+		a, b := m.projectOverviews[projects[i]], m.projectOverviews[projects[j]]
+		var less, equal bool
+		switch m.projectSort {
+		case sortProjectSize:
+			less, equal = a.Size < b.Size, a.Size == b.Size
+		case sortProjectLastEntry:
+			less, equal = a.LastEntry.Before(b.LastEntry), a.LastEntry.Equal(b.LastEntry)
+		case sortProjectErrors:
+			less, equal = a.ErrorsToday < b.ErrorsToday, a.ErrorsToday == b.ErrorsToday
+		case sortProjectFiles:
+			less, equal = a.Files < b.Files, a.Files == b.Files
+		default:
+			less, equal = projects[i] < projects[j], projects[i] == projects[j]
+		}
+		if equal {
+			return projects[i] < projects[j]
+		}
+		return less != m.projectSortDesc
+	})
+	m.globalProjects = projects
+
+	for i, project := range projects {
+		if project == selected {
+			m.cursor = i
+		}
+	}
+}
+
+// overviewHeader is the column header of the global project list, with a
+// marker on the sort column.
+func (m Model) overviewHeader() string {
+	title := func(name string, column projectSort, width int, right bool) string {
+		if column == m.projectSort && (column != sortProjectName || m.projectSortDesc) {
+			if m.projectSortDesc {
+				name += " ▼"
+			} else {
+				name += " ▲"
+			}
+		}
+		if right {
+			return rightCell(name, width)
+		}
+		return cell(name, width)
+	}
+	return strings.Join([]string{
+		title("PROJECT", sortProjectName, overviewNameWidth, false),
+		title("FILES", sortProjectFiles, overviewFilesWidth, true),
+		title("SIZE", sortProjectSize, overviewSizeWidth, true),
+		cell("DAYS", overviewDaysWidth),
+		title("LAST ENTRY", sortProjectLastEntry, overviewLastWidth, false),
+		title("ERRORS", sortProjectErrors, overviewErrorsWidth, true),
+		cell("LOGGER", overviewLoggerWidth),
+	}, tableGap)
+}
+
+// rightCell right-aligns text in width columns.
+func rightCell(text string, width int) string {
+	return strings.Repeat(" ", max(0, width-lipgloss.Width(text))) + text
+}
+
+// overviewRow lays out a project's overview in the list's columns. Numbers
+// not read yet are left blank.
+func (m Model) overviewRow(project string) string {
+	overview, ok := m.projectOverviews[project]
+	if !ok {
+		return cell(project, overviewNameWidth)
+	}
+
+	days := "-"
+	if !overview.FirstDay.IsZero() {
+		days = overview.FirstDay.Format("Jan 02")
+		if !overview.LastDay.Equal(overview.FirstDay) {
+			days += " – " + overview.LastDay.Format("Jan 02")
+		}
+	}
+	last := "-"
+	if !overview.LastEntry.IsZero() {
+		last = overview.LastEntry.Format("Jan 02 15:04")
+	}
+
+	return strings.Join([]string{
+		cell(project, overviewNameWidth),
+		fmt.Sprintf("%*d", overviewFilesWidth, overview.Files),
+		fmt.Sprintf("%*s", overviewSizeWidth, formatSize(overview.Size)),
+		cell(days, overviewDaysWidth),
+		cell(last, overviewLastWidth),
+		fmt.Sprintf("%*d", overviewErrorsWidth, overview.ErrorsToday),
+		cell(overview.logger, overviewLoggerWidth),
+	}, tableGap)
+}
+
+// overviewFooter totals the disk used under the log root and shows where
+// the selected project's logs come from.
+func (m Model) overviewFooter() string {
+	var total int64
+	for _, overview := range m.projectOverviews {
+		total += overview.Size
+	}
+	root, _ := detector.LogRoot()
+	footer := fmt.Sprintf("Total: %s in %d projects under %s", formatSize(total), len(m.globalProjects), root)
+
+	if m.cursor < len(m.globalProjects) {
+		if source := m.projectOverviews[m.globalProjects[m.cursor]].source; source != "" {
+			footer += "\nSource: " + source
+		}
+	}
+	return footer
+}
@@ -0,0 +1,211 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/LFroesch/logdog/internal/detector"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// selectedLogDir is the log directory of the project under the cursor on
+// the global list.
+func (m Model) selectedLogDir() (project, dir string, err error) {
+	if m.cursor >= len(m.globalProjects) {
+		return "", "", fmt.Errorf("no project selected")
+	}
+	root, err := detector.LogRoot()
+	if err != nil {
+		return "", "", err
+	}
+	project = m.globalProjects[m.cursor]
+	return project, filepath.Join(root, project), nil
+}
+
+// handleProjectRemoval asks to confirm deleting or archiving the selected
+// project's log directory.
+func (m Model) handleProjectRemoval(action logDirAction) (Model, tea.Cmd) {
+	project, _, err := m.selectedLogDir()
+	if err != nil {
+		return m, nil
+	}
+
+	what := "all logs of " + project
+	if overview, ok := m.projectOverviews[project]; ok {
+		what += fmt.Sprintf(" (%d files, %s)", overview.Files, formatSize(overview.Size))
+	}
+	if action == logDirArchive {
+		m.message = fmt.Sprintf("Archive %s to a tarball and remove the directory? Press 'y' to confirm, any other key to cancel", what)
+	} else {
+		m.message = fmt.Sprintf("Delete %s? Press 'y' to confirm, any other key to cancel", what)
+	}
+	m.confirmingProject = action
+	return m, nil
+}
+
+func (m Model) confirmProjectRemoval() (Model, tea.Cmd) {
+	action := m.confirmingProject
+	m.confirmingProject = logDirKeep
+	project, dir, err := m.selectedLogDir()
+	if err != nil {
+		m.message = fmt.Sprintf("❌ %v", err)
+		return m, nil
+	}
+
+	if action == logDirArchive {
+		archive, err := detector.ArchiveLogDir(dir)
+		if err != nil {
+			m.message = fmt.Sprintf("❌ %v", err)
+			return m.reloadProjects(project)
+		}
+		m.message = fmt.Sprintf("✅ Archived %s to %s", project, archive)
+	} else {
+		if err := detector.RemoveLogDir(dir); err != nil {
+			m.message = fmt.Sprintf("❌ %v", err)
+			return m.reloadProjects(project)
+		}
+		m.message = fmt.Sprintf("✅ Deleted %s", project)
+	}
+	m.forgetProject(project, "")
+	return m.reloadProjects("")
+}
+
+// startProjectRename opens the prompt for the selected project's new name.
+func (m Model) startProjectRename() (Model, tea.Cmd) {
+	project, _, err := m.selectedLogDir()
+	if err != nil {
+		return m, nil
+	}
+	m.renamingProject = true
+	return m.startEdit(project)
+}
+
+func (m Model) commitProjectRename(name string) (Model, tea.Cmd) {
+	m.renamingProject = false
+	name = strings.TrimSpace(name)
+	project, dir, err := m.selectedLogDir()
+	if err != nil || name == project {
+		return m, nil
+	}
+
+	if _, err := detector.RenameLogDir(dir, name); err != nil {
+		m.message = fmt.Sprintf("❌ %v", err)
+		return m.reloadProjects(project)
+	}
+	m.forgetProject(project, name)
+	m.message = fmt.Sprintf("✅ Renamed %s to %s", project, name)
+	return m.reloadProjects(name)
+}
+
+// startProjectMerge opens the prompt for the project to merge the selected
+// one into, suggesting the one marked if there is exactly one.
+func (m Model) startProjectMerge() (Model, tea.Cmd) {
+	project, _, err := m.selectedLogDir()
+	if err != nil {
+		return m, nil
+	}
+	var marked []string
+	for name, ok := range m.markedProjects {
+		if ok && name != project {
+			marked = append(marked, name)
+		}
+	}
+	target := ""
+	if len(marked) == 1 {
+		target = marked[0]
+	}
+	m.mergingProject = true
+	return m.startEdit(target)
+}
+
+func (m Model) commitProjectMerge(target string) (Model, tea.Cmd) {
+	m.mergingProject = false
+	target = strings.TrimSpace(target)
+	project, dir, err := m.selectedLogDir()
+	if err != nil || target == "" {
+		return m, nil
+	}
+
+	if err := detector.MergeLogDirs(dir, target); err != nil {
+		m.message = fmt.Sprintf("❌ %v", err)
+		return m.reloadProjects(project)
+	}
+	m.forgetProject(project, target)
+	m.message = fmt.Sprintf("✅ Merged %s into %s", project, target)
+	return m.reloadProjects(target)
+}
+
+// forgetProject drops what is remembered about a project whose log
+// directory is gone. If its logs moved to another project, that one keeps
+// its own state and table settings, taking over the source and settings
+// when it has none.
+func (m *Model) forgetProject(project, movedTo string) {
+	projects := make(map[string]detector.ProjectState, len(m.state.Projects))
+	for name, state := range m.state.Projects {
+		projects[name] = state
+	}
+	old, ok := projects[project]
+	delete(projects, project)
+	if movedTo != "" && ok {
+		if existing, exists := projects[movedTo]; exists {
+			if existing.Source == "" {
+				existing.Source = old.Source
+			}
+			projects[movedTo] = existing
+		} else {
+			old.File = ""
+			old.Line = ""
+			old.Entry = 0
+			projects[movedTo] = old
+		}
+	}
+	m.state.Projects = projects
+	if err := detector.SaveState(m.state); err != nil {
+		m.message = fmt.Sprintf("❌ Failed to save state: %v", err)
+	}
+
+	if settings, ok := m.global.Projects[project]; ok {
+		saved := make(map[string]detector.ProjectSettings, len(m.global.Projects))
+		for name, settings := range m.global.Projects {
+			saved[name] = settings
+		}
+		delete(saved, project)
+		if _, exists := saved[movedTo]; movedTo != "" && !exists {
+			saved[movedTo] = settings
+		}
+		m.global.Projects = saved
+		if err := detector.SaveGlobalConfig(m.global); err != nil {
+			m.message = fmt.Sprintf("❌ Failed to save table settings: %v", err)
+		}
+	}
+
+	delete(m.projectOverviews, project)
+	delete(m.projectAnomalies, project)
+	delete(m.projectChanges, project)
+	delete(m.markedProjects, project)
+}
+
+// reloadProjects lists the log root again after a project changed, with
+// the cursor on selected if it is still there, and reads the overviews
+// anew.
+func (m Model) reloadProjects(selected string) (Model, tea.Cmd) {
+	m.globalProjects = scanGlobalProjects()
+	m.cursor = max(0, min(m.cursor, len(m.globalProjects)-1))
+	for i, project := range m.globalProjects {
+		if project == selected {
+			m.cursor = i
+		}
+	}
+	m.sortProjects()
+	return m, tea.Batch(scanAnomalies(m.globalProjects), scanOverviews(m.globalProjects, m.state))
+}
+
+// projectPrompt is the footer line while renaming or merging a project.
+func (m Model) projectPrompt() string {
+	project, _, _ := m.selectedLogDir()
+	if m.mergingProject {
+		return fmt.Sprintf("Merge %s into: %s█  •  ENTER merge, ESC cancel", project, m.editValue)
+	}
+	return fmt.Sprintf("Rename %s to: %s█  •  ENTER rename, ESC cancel", project, m.editValue)
+}
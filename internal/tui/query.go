@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/logs"
+	"github.com/LFroesch/logdog/internal/query"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxQueryResults caps how many matching entries are loaded into the viewer.
+const maxQueryResults = 100000
+
+// queryDoneMsg carries the result of a query run in the background.
+type queryDoneMsg struct {
+	query   *query.Query
+	sources []logs.Source
+	files   int
+	entries []logs.Entry
+	err     error
+	// trace is set when the query was started by tracing an entry.
+	trace *trace
+}
+
+// startQuery opens the : prompt, pre-filled with the active query.
+func (m Model) startQuery() (Model, tea.Cmd) {
+	m.querying = true
+	text := ""
+	if m.activeQuery != nil {
+		text = m.activeQuery.String()
+	}
+	return m.startEdit(text)
+}
+
+// querySources are the log directories a query from the current screen
+// runs over.
+func (m Model) querySources() []logs.Source {
+	if m.screen == screenLogView {
+		return m.viewSources
+	}
+	if m.cursor < len(m.logFiles) {
+		return []logs.Source{fileSource(m.logFiles[m.cursor])}
+	}
+	return nil
+}
+
+// fileSource is the project log directory holding a log file.
+func fileSource(path string) logs.Source {
+	dir := filepath.Dir(path)
+	return logs.Source{Name: filepath.Base(dir), Dir: dir}
+}
+
+func (m Model) commitQuery(input string) (Model, tea.Cmd) {
+	m.querying = false
+	q, err := query.Parse(input, time.Now())
+	if err != nil {
+		m.message = fmt.Sprintf("❌ Invalid query: %v", err)
+		return m, nil
+	}
+
+	sources := m.querySources()
+	if len(sources) == 0 {
+		m.message = "❌ No log directory to query"
+		return m, nil
+	}
+	if m.activeQuery == nil {
+		// Where ESC goes back to once done with the results.
+		m.queryReturn = ""
+		if m.screen == screenLogView {
+			m.queryReturn = m.viewingFile
+		}
+	}
+
+	m.message = "Running query..."
+	return m, runQuery(q, sources)
+}
+
+// runQuery runs a query over every source's files, merging the matches of
+// several projects by time.
+func runQuery(q *query.Query, sources []logs.Source) tea.Cmd {
+	return func() tea.Msg {
+		done := queryDoneMsg{query: q, sources: sources}
+		var streams [][]logs.Entry
+		found := 0
+		for _, source := range sources {
+			if found >= maxQueryResults {
+				break
+			}
+			files, err := q.Files(source.Dir)
+			if err != nil {
+				done.err = err
+				return done
+			}
+			done.files += len(files)
+
+			var entries []logs.Entry
+			err = q.Run(files, func(_ string, entry logs.Entry) error {
+				entry.Source = source.Name
+				entries = append(entries, entry)
+				if found++; found >= maxQueryResults {
+					return query.ErrStop
+				}
+				return nil
+			})
+			if err != nil {
+				done.err = err
+				return done
+			}
+			streams = append(streams, entries)
+		}
+		done.entries = logs.Merge(streams...)
+		return done
+	}
+}
+
+// showQueryResults opens the viewer on the entries a query found.
+func (m Model) showQueryResults(msg queryDoneMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.message = fmt.Sprintf("❌ Query failed: %v", msg.err)
+		return m, nil
+	}
+
+	m.activeQuery = msg.query
+	m.activeTrace = msg.trace
+	m.queryFiles = msg.files
+	m.viewingFile = ""
+	m.viewSources = msg.sources
+	if msg.trace != nil {
+		m.viewSources = matchedSources(msg.sources, msg.entries)
+	}
+	m.activeGroup = nil
+	m.timeline = nil
+	m.showEntries(msg.entries)
+
+	switch {
+	case len(msg.entries) >= maxQueryResults:
+		m.message = fmt.Sprintf("Showing the first %d matches", maxQueryResults)
+	case len(msg.entries) == 0:
+		m.message = "No entries match"
+	default:
+		m.message = ""
+	}
+	return m, nil
+}
+
+// matchedSources keeps the sources that entries came from, so a trace over
+// every project only tags and colors the ones it passed through.
+func matchedSources(sources []logs.Source, entries []logs.Entry) []logs.Source {
+	found := map[string]bool{}
+	for _, entry := range entries {
+		found[entry.Source] = true
+	}
+	var matched []logs.Source
+	for _, source := range sources {
+		if found[source.Name] {
+			matched = append(matched, source)
+		}
+	}
+	return matched
+}
+
+// closeQuery leaves the query results for the file or list they were
+// started from.
+func (m Model) closeQuery() (Model, tea.Cmd) {
+	m.rememberVisit()
+	m.activeQuery = nil
+	m.activeTrace = nil
+	if m.queryReturn != "" {
+		return m.openLogFile(m.queryReturn)
+	}
+	m.screen = screenLogs
+	m.message = ""
+	return m, nil
+}
+
+// queryStatus is the footer line while the : prompt is open.
+func (m Model) queryStatus() string {
+	return ":" + m.editValue + "█  •  e.g. level>=WARN and user_id=123 message~timeout since 2h, ENTER run, ESC cancel"
+}
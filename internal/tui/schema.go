@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LFroesch/logdog/internal/stats"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// schemaKeyWidth caps the width of the key column of the fields report.
+const schemaKeyWidth = 30
+
+func (m Model) renderSchema() string {
+	return m.renderReport("🧬 Fields: " + m.statsProject())
+}
+
+// formatSchema lists every data key with its types, frequency, cardinality
+// and example values, after warnings about inconsistent keys.
+func (m Model) formatSchema(report *stats.Report) []string {
+	width := m.statsView.width
+	if width <= 0 {
+		width = 80
+	}
+	heading := lipgloss.NewStyle().Bold(true).Foreground(m.theme().Accent)
+	muted := lipgloss.NewStyle().Foreground(m.theme().Muted)
+	text := lipgloss.NewStyle().Foreground(m.theme().Text)
+	warn := lipgloss.NewStyle().Foreground(m.theme().levelColor("WARN"))
+
+	fields := report.Fields()
+	if len(fields) == 0 {
+		return []string{text.Render("No data fields found")}
+	}
+
+	var lines []string
+	var warnings []string
+	for _, keys := range report.DuplicateKeys() {
+		warnings = append(warnings, "⚠ Near-duplicate keys: "+strings.Join(keys, ", "))
+	}
+	for _, field := range fields {
+		if field.MixedTypes() {
+			var types []string
+			for _, name := range field.TypeNames() {
+				types = append(types, fmt.Sprintf("%s %s", name, percent(field.Types[name], field.Count)))
+			}
+			warnings = append(warnings, fmt.Sprintf("⚠ %s has mixed types: %s", field.Key, strings.Join(types, ", ")))
+		}
+	}
+	if len(warnings) > 0 {
+		lines = append(lines, heading.Render("Consistency"))
+		for _, warning := range warnings {
+			lines = append(lines, warn.Render(cell(warning, width)))
+		}
+		lines = append(lines, "")
+	}
+
+	keyWidth := len("KEY")
+	for _, field := range fields {
+		keyWidth = max(keyWidth, min(schemaKeyWidth, len(field.Key)))
+	}
+	const seenWidth, typesWidth, distinctWidth = 14, 20, 8
+	examplesWidth := max(10, width-keyWidth-seenWidth-typesWidth-distinctWidth-4*len(tableGap))
+
+	lines = append(lines, heading.Render(fmt.Sprintf("%d keys in %d entries", len(fields), report.Entries)))
+	lines = append(lines, muted.Render(strings.Join([]string{
+		cell("KEY", keyWidth),
+		cell("SEEN", seenWidth),
+		cell("TYPES", typesWidth),
+		fmt.Sprintf("%*s", distinctWidth, "DISTINCT"),
+		"EXAMPLES",
+	}, tableGap)))
+
+	for _, field := range fields {
+		distinct, capped := field.Cardinality()
+		cardinality := fmt.Sprint(distinct)
+		if capped {
+			cardinality += "+"
+		} else if distinct == 0 {
+			// Objects are described by their nested keys instead.
+			cardinality = "-"
+		}
+		examples := make([]string, len(field.Examples))
+		for i, example := range field.Examples {
+			examples[i] = strings.Join(strings.Fields(example), " ")
+		}
+
+		style := text
+		if field.MixedTypes() {
+			style = warn
+		}
+		lines = append(lines, style.Render(strings.Join([]string{
+			cell(field.Key, keyWidth),
+			cell(fmt.Sprintf("%d (%s)", field.Count, percent(field.Count, report.Entries)), seenWidth),
+			cell(strings.Join(field.TypeNames(), "|"), typesWidth),
+			fmt.Sprintf("%*s", distinctWidth, cardinality),
+			cell(strings.Join(examples, ", "), examplesWidth),
+		}, tableGap)))
+	}
+	return lines
+}
@@ -0,0 +1,237 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/LFroesch/logdog/internal/logs"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// compileSearch turns a search query into a regexp. Plain queries match
+// literally; either kind is case-insensitive unless it contains an
+// uppercase letter.
+func compileSearch(query string, useRegex bool) (*regexp.Regexp, error) {
+	if query == "" {
+		return nil, nil
+	}
+	pattern := query
+	if !useRegex {
+		pattern = regexp.QuoteMeta(query)
+	}
+	if strings.ToLower(query) == query {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// entryMatches reports whether re matches the entry's message or any of its
+// data values. Lines that aren't JSON are matched as a whole.
+func entryMatches(re *regexp.Regexp, entry logs.Entry) bool {
+	if !entry.Valid {
+		return re.MatchString(entry.Raw)
+	}
+	if re.MatchString(entry.Message) {
+		return true
+	}
+	for _, v := range entry.Data {
+		if re.MatchString(logs.FormatValue(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// highlight renders text in base, picking out the current search matches.
+func (m Model) highlight(text string, base lipgloss.Style) string {
+	if m.searchRe == nil {
+		return base.Render(text)
+	}
+	spans := m.searchRe.FindAllStringIndex(text, -1)
+	if len(spans) == 0 {
+		return base.Render(text)
+	}
+
+	hl := base.
+		Background(m.theme().Message).
+		Foreground(lipgloss.Color("0"))
+
+	var b strings.Builder
+	last := 0
+	for _, span := range spans {
+		if span[0] == span[1] {
+			continue
+		}
+		b.WriteString(base.Render(text[last:span[0]]))
+		b.WriteString(hl.Render(text[span[0]:span[1]]))
+		last = span[1]
+	}
+	b.WriteString(base.Render(text[last:]))
+	return b.String()
+}
+
+func (m *Model) clearSearch() {
+	m.searching = false
+	m.searchQuery = ""
+	m.searchRe = nil
+	m.searchErr = nil
+	m.searchMatches = nil
+	m.searchCurrent = -1
+}
+
+// startSearch opens the search prompt. Matches are found as the query is
+// typed, starting from the entry under the cursor.
+func (m Model) startSearch(backward bool) (Model, tea.Cmd) {
+	m.searching = true
+	m.searchBackward = backward
+	m.searchOrigin = m.entryCursor
+	return m.startEdit("")
+}
+
+// applySearch compiles query, recomputes the matching entries and jumps to
+// the first one at or after from (before it, searching backward).
+func (m *Model) applySearch(query string, from int) {
+	m.searchQuery = query
+	m.searchRe, m.searchErr = compileSearch(query, m.searchRegex)
+	m.findMatches()
+
+	m.refreshViewer()
+	if len(m.searchMatches) > 0 {
+		m.jumpToMatch(from, m.searchBackward, true)
+	}
+}
+
+// findMatches recomputes which visible entries match the current search.
+func (m *Model) findMatches() {
+	m.searchMatches = nil
+	m.searchCurrent = -1
+	if m.searchRe == nil {
+		return
+	}
+	for i, entry := range m.viewEntries {
+		if entryMatches(m.searchRe, entry) {
+			m.searchMatches = append(m.searchMatches, i)
+		}
+	}
+}
+
+// jumpToMatch moves to the nearest match from entry `from` in the given
+// direction, wrapping around the ends. inclusive allows `from` itself.
+func (m *Model) jumpToMatch(from int, backward, inclusive bool) {
+	n := len(m.searchMatches)
+	if n == 0 {
+		return
+	}
+
+	target := -1
+	if backward {
+		for i := n - 1; i >= 0; i-- {
+			if e := m.searchMatches[i]; e < from || (inclusive && e == from) {
+				target = i
+				break
+			}
+		}
+		if target < 0 {
+			target = n - 1
+			m.message = "search wrapped to bottom"
+		}
+	} else {
+		for i, e := range m.searchMatches {
+			if e > from || (inclusive && e == from) {
+				target = i
+				break
+			}
+		}
+		if target < 0 {
+			target = 0
+			m.message = "search wrapped to top"
+		}
+	}
+
+	m.searchCurrent = target
+	m.entryCursor = m.searchMatches[target]
+	m.viewer.ScrollToItem(m.entryCursor)
+}
+
+// nextMatch steps through matches: n continues in the search's direction,
+// N goes the other way.
+func (m Model) nextMatch(reverse bool) (Model, tea.Cmd) {
+	if m.searchRe == nil {
+		m.message = "No active search, press / to search"
+		return m, nil
+	}
+	if len(m.searchMatches) == 0 {
+		m.message = fmt.Sprintf("Pattern not found: %s", m.searchQuery)
+		return m, nil
+	}
+
+	m.message = ""
+	m.jumpToMatch(m.entryCursor, m.searchBackward != reverse, false)
+	return m, nil
+}
+
+func (m Model) previewSearch(query string) Model {
+	m.message = ""
+	m.applySearch(query, m.searchOrigin)
+	return m
+}
+
+func (m Model) commitSearch(query string) (Model, tea.Cmd) {
+	m.searching = false
+	m = m.previewSearch(query)
+	if query != "" && m.searchErr == nil && len(m.searchMatches) == 0 {
+		m.message = fmt.Sprintf("Pattern not found: %s", query)
+	}
+	return m, nil
+}
+
+func (m Model) cancelSearch() Model {
+	m.clearSearch()
+	m.refreshViewer()
+	m.entryCursor = m.searchOrigin
+	m.viewer.ScrollToItem(m.searchOrigin)
+	return m
+}
+
+func (m Model) toggleSearchRegex() Model {
+	m.searchRegex = !m.searchRegex
+	return m.previewSearch(m.editValue)
+}
+
+// searchStatus is the footer line for the search prompt or the active search.
+func (m Model) searchStatus() string {
+	var b strings.Builder
+
+	if m.searching {
+		prefix := "/"
+		if m.searchBackward {
+			prefix = "?"
+		}
+		b.WriteString(prefix + m.editValue + "█")
+	} else if m.searchQuery != "" {
+		b.WriteString("search: " + m.searchQuery)
+	} else {
+		return ""
+	}
+
+	if m.searchRegex {
+		b.WriteString("  [regex]")
+	}
+
+	switch {
+	case m.searchErr != nil:
+		b.WriteString("  invalid pattern")
+	case m.searchRe != nil && len(m.searchMatches) == 0:
+		b.WriteString("  no matches")
+	case len(m.searchMatches) > 0:
+		current := m.searchCurrent + 1
+		b.WriteString(fmt.Sprintf("  match %d/%d", current, len(m.searchMatches)))
+	}
+
+	if m.searching {
+		b.WriteString("  •  ENTER search, ctrl+r regex, ESC cancel")
+	}
+	return b.String()
+}
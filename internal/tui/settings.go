@@ -0,0 +1,286 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/LFroesch/logdog/internal/detector"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Settings form fields, in display order. The fields from settingLogLevel to
+// settingPackageName edit a detector.Config, either the project's
+// .logdog.json or the global defaults; the rest are always global.
+const (
+	settingScope = iota
+	settingLogLevel
+	settingOutputDir
+	settingMaxFiles
+	settingRetention
+	settingPackagePath
+	settingPackageName
+	settingViewerLevel
+	settingTheme
+	settingLogRoot
+	settingCorrelationKeys
+	settingCount
+)
+
+var logLevels = []string{"DEBUG", "INFO", "WARN", "ERROR"}
+
+// levelAtLeast reports whether level is at or above min. Unknown levels are
+// always shown.
+func levelAtLeast(level, min string) bool {
+	r := levelRank(level)
+	return r < 0 || r >= levelRank(min)
+}
+
+// levelRank is the position of level in logLevels, or -1 if it isn't one.
+func levelRank(level string) int {
+	for i, v := range logLevels {
+		if strings.EqualFold(v, level) {
+			return i
+		}
+	}
+	return -1
+}
+
+// cycle returns the value after current in values, wrapping around.
+func cycle(values []string, current string) string {
+	for i, v := range values {
+		if strings.EqualFold(v, current) {
+			return values[(i+1)%len(values)]
+		}
+	}
+	return values[0]
+}
+
+// settingsConfig returns the config the form is editing.
+func (m *Model) settingsConfig() *detector.Config {
+	if m.settingsGlobal {
+		return &m.global.Defaults
+	}
+	return &m.config
+}
+
+// fieldIsGlobal reports whether the field under the cursor is saved to the
+// global config rather than the project's.
+func (m Model) fieldIsGlobal() bool {
+	return m.settingsGlobal || m.cursor >= settingViewerLevel
+}
+
+func (m Model) handleSettingsField() (Model, tea.Cmd) {
+	config := m.settingsConfig()
+
+	switch m.cursor {
+	case settingScope:
+		if m.language == nil {
+			m.message = "No project detected, only global settings can be edited"
+			return m, nil
+		}
+		m.settingsGlobal = !m.settingsGlobal
+		m.message = ""
+		return m, nil
+	case settingLogLevel:
+		config.LogLevel = cycle(logLevels, config.LogLevel)
+	case settingViewerLevel:
+		m.global.ViewerLevel = cycle(logLevels, m.global.ViewerLevel)
+		m.hiddenLevels = hiddenLevelsBelow(m.global.ViewerLevel)
+	case settingTheme:
+		m.global.Theme = cycle(themeNames, m.global.Theme)
+	case settingOutputDir:
+		return m.startEdit(config.OutputDir)
+	case settingMaxFiles:
+		return m.startEdit(strconv.Itoa(config.MaxFiles))
+	case settingRetention:
+		return m.startEdit(config.Retention.String())
+	case settingPackagePath:
+		return m.startEdit(config.PackagePath)
+	case settingPackageName:
+		return m.startEdit(config.PackageName)
+	case settingLogRoot:
+		return m.startEdit(m.global.LogRoot)
+	case settingCorrelationKeys:
+		return m.startEdit(strings.Join(m.global.CorrelationKeys, " "))
+	}
+	return m.saveSettings(m.fieldIsGlobal())
+}
+
+func (m Model) commitSettingsField(value string) (Model, tea.Cmd) {
+	config := m.settingsConfig()
+	value = strings.TrimSpace(value)
+
+	switch m.cursor {
+	case settingOutputDir:
+		if err := detector.CheckOutputDir(m.projectPath, value); err != nil {
+			m.message = fmt.Sprintf("❌ %v", err)
+			return m, nil
+		}
+		config.OutputDir = value
+	case settingPackagePath:
+		config.PackagePath = value
+	case settingPackageName:
+		config.PackageName = value
+	case settingLogRoot:
+		m.global.LogRoot = value
+	case settingCorrelationKeys:
+		m.global.CorrelationKeys = strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || r == ' '
+		})
+		if len(m.global.CorrelationKeys) == 0 {
+			m.global.CorrelationKeys = detector.DefaultGlobalConfig().CorrelationKeys
+		}
+	case settingMaxFiles:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			m.message = fmt.Sprintf("❌ %q is not a positive number", value)
+			return m, nil
+		}
+		config.MaxFiles = n
+	case settingRetention:
+		policy, err := detector.ParseRetention(value, config.Retention)
+		if err != nil {
+			m.message = fmt.Sprintf("❌ %v", err)
+			return m, nil
+		}
+		config.Retention = policy
+	}
+	return m.saveSettings(m.fieldIsGlobal())
+}
+
+// adjustSetting nudges the numeric field under the cursor: max files, or
+// every level's retention period.
+func (m Model) adjustSetting(delta int) (Model, tea.Cmd) {
+	config := m.settingsConfig()
+
+	switch m.cursor {
+	case settingMaxFiles:
+		if n := config.MaxFiles + delta; n >= 1 {
+			config.MaxFiles = n
+		}
+	case settingRetention:
+		config.Retention = config.Retention.Shift(delta, 365)
+	default:
+		return m, nil
+	}
+	return m.saveSettings(m.settingsGlobal)
+}
+
+// saveSettings writes the global config when global is set, else the
+// project's .logdog.json.
+func (m Model) saveSettings(global bool) (Model, tea.Cmd) {
+	var err error
+	var target string
+
+	if global {
+		target, _ = detector.GlobalConfigPath()
+		err = detector.SaveGlobalConfig(m.global)
+		if m.cursor == settingLogRoot {
+			m.globalProjects = scanGlobalProjects()
+		}
+	} else {
+		target = detector.ProjectConfigFile
+		err = m.saveProjectConfig()
+	}
+
+	if err != nil {
+		m.message = fmt.Sprintf("❌ Not saved: %v", err)
+	} else {
+		m.message = fmt.Sprintf("✅ Saved to %s", target)
+	}
+	return m, nil
+}
+
+func (m Model) renderSettings() string {
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme().Accent).
+		Render("⚙️ Settings")
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(m.theme().SelectedBg).
+		Foreground(m.theme().SelectedFg)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(m.theme().Text)
+
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(m.theme().Muted)
+
+	config := m.settingsConfig()
+
+	scope := "Project (" + detector.ProjectConfigFile + ")"
+	if m.settingsGlobal {
+		scope = "Global defaults"
+	}
+	outputDir := config.OutputDir
+	if outputDir == "" {
+		outputDir = "(default: <log root>/<project>)"
+	}
+	logRoot := m.global.LogRoot
+	if logRoot == "" {
+		logRoot = "(default: ~/logdog)"
+	}
+
+	values := []string{
+		scope,
+		config.LogLevel,
+		outputDir,
+		strconv.Itoa(config.MaxFiles),
+		config.Retention.String(),
+		config.PackagePath,
+		config.PackageName,
+		m.global.ViewerLevel,
+		m.global.Theme,
+		logRoot,
+		strings.Join(m.global.CorrelationKeys, ", "),
+	}
+	labels := []string{
+		"Editing:",
+		"Log level:",
+		"Output dir:",
+		"Max files:",
+		"Retention:",
+		"Package path:",
+		"Package name:",
+		"Viewer level:",
+		"Theme:",
+		"Log root:",
+		"Trace keys:",
+	}
+
+	var rows []string
+	for i := range labels {
+		if i == settingViewerLevel {
+			rows = append(rows, "", sectionStyle.Render("  Global (all projects)"))
+		}
+		row := fmt.Sprintf("%-15s %s", labels[i], m.fieldValue(i == m.cursor, values[i]))
+		if i == m.cursor {
+			rows = append(rows, selectedStyle.Render("> "+row))
+		} else {
+			rows = append(rows, normalStyle.Render("  "+row))
+		}
+	}
+
+	help := "\nPress SPACE/ENTER to edit or cycle a field, +/- to adjust numbers, ESC to go back"
+	if m.editing {
+		help = "\nType a value, ENTER to save, ESC to discard"
+		if m.cursor == settingRetention {
+			help = "\nType levels and days, e.g. ERROR 90 WARN 30 INFO 14 DEBUG 2, ENTER to save, ESC to discard"
+		}
+	}
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme().Muted).
+		Render(help)
+
+	messageStr := ""
+	if m.message != "" {
+		messageStr = "\n\n" + lipgloss.NewStyle().
+			Foreground(m.theme().Message).
+			Render(m.message)
+	}
+
+	return fmt.Sprintf("%s\n\n%s\n%s%s", header, strings.Join(rows, "\n"), instructions, messageStr)
+}
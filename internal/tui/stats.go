@@ -0,0 +1,463 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/stats"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// topMessageCount and busiestHourCount are how many rows those sections
+	// of the stats screen list.
+	topMessageCount  = 10
+	busiestHourCount = 5
+	// statsLabelWidth is the width of the row labels in front of charts.
+	statsLabelWidth = 7
+	statsBarWidth   = 24
+)
+
+// sparkBlocks are the glyphs of a sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// statsProgressMsg reports how far collecting stats has got. updates is
+// where the next message will come from; id ties it to the stats screen
+// that started the collection, so results for one already left are dropped.
+type statsProgressMsg struct {
+	id      int
+	done    int64
+	total   int64
+	updates <-chan tea.Msg
+}
+
+// statsDoneMsg carries the finished report.
+type statsDoneMsg struct {
+	id     int
+	report *stats.Report
+	err    error
+}
+
+// openStats shows target, one of the screens built from a stats.Report, for
+// the files in the log list, reading them in the background.
+func (m Model) openStats(target screen) (Model, tea.Cmd) {
+	m.statsID++
+	m.statsReport = nil
+	m.groups = nil
+	m.statsDone, m.statsTotal = 0, 0
+	m.statsView = viewport{}
+	m.screen = target
+	m.groupCursor = 0
+	m.numbers = nil
+	m.numbersPending = false
+	m.message = ""
+	m.resizeStats()
+	return m, collectStats(m.statsID, append([]string(nil), m.logFiles...))
+}
+
+// collectStats reads the files into a report in the background.
+func collectStats(id int, files []string) tea.Cmd {
+	return streamStats(id, func(progress func(done, total int64)) tea.Msg {
+		report, err := stats.Collect(files, progress)
+		return statsDoneMsg{id: id, report: report, err: err}
+	})
+}
+
+// streamStats runs a pass over log files on a goroutine, sending progress as
+// it goes and the message run returns at the end.
+func streamStats(id int, run func(progress func(done, total int64)) tea.Msg) tea.Cmd {
+	updates := make(chan tea.Msg, 1)
+	go func() {
+		msg := run(func(done, total int64) {
+			// Progress is dropped rather than waited on if the last update
+			// hasn't been picked up yet.
+			select {
+			case updates <- statsProgressMsg{id: id, done: done, total: total, updates: updates}:
+			default:
+			}
+		})
+		updates <- msg
+	}()
+	return waitForStats(updates)
+}
+
+func waitForStats(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+func (m Model) statsProgress(msg statsProgressMsg) (Model, tea.Cmd) {
+	if msg.id == m.statsID {
+		m.statsDone, m.statsTotal = msg.done, msg.total
+	}
+	// Keep draining even for a stale collection so its goroutine can finish.
+	return m, waitForStats(msg.updates)
+}
+
+func (m Model) showStats(msg statsDoneMsg) (Model, tea.Cmd) {
+	if msg.id != m.statsID || !m.reportScreen() {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.message = fmt.Sprintf("❌ Failed to read stats: %v", msg.err)
+		return m, nil
+	}
+	m.statsReport = msg.report
+	m.groups = msg.report.Groups()
+	m.refreshStats()
+	return m, nil
+}
+
+// reportScreen reports whether the current screen shows a stats.Report.
+func (m Model) reportScreen() bool {
+	return m.screen == screenStats || m.screen == screenGroups || m.screen == screenSchema ||
+		m.screen == screenNumbers
+}
+
+func (m *Model) resizeStats() {
+	width, height := 0, 0
+	if m.width > 0 {
+		width = max(20, m.width-6)
+	}
+	if m.height > 0 {
+		height = max(3, m.height-8)
+	}
+	m.statsView.SetSize(width, height)
+	m.refreshStats()
+}
+
+// refreshStats lays the report out for the current width.
+func (m *Model) refreshStats() {
+	if m.statsReport == nil {
+		return
+	}
+	switch m.screen {
+	case screenSchema:
+		m.statsView.SetItems(m.formatSchema(m.statsReport))
+		return
+	case screenNumbers:
+		m.statsView.SetItems(m.formatNumbers(m.statsReport))
+		return
+	}
+	m.statsView.SetItems(m.formatStats(m.statsReport))
+}
+
+func (m Model) handleStatsKey(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		if m.numbers != nil || m.numbersPending {
+			m.statsID++
+			m.numbers = nil
+			m.numbersPending = false
+			m.refreshStats()
+			m.statsView.GotoTop()
+			break
+		}
+		m.screen = screenLogs
+		m.statsID++
+		m.statsReport = nil
+		m.statsView = viewport{}
+	case "a":
+		if m.screen != screenNumbers || m.statsReport == nil {
+			return nil, nil
+		}
+		m, cmd := m.startNumbers()
+		return &m, cmd
+	case "r":
+		m, cmd := m.openStats(m.screen)
+		return &m, cmd
+	case "up", "k":
+		m.statsView.ScrollUp(1)
+	case "down", "j":
+		m.statsView.ScrollDown(1)
+	case "pgup", "b":
+		m.statsView.PageUp()
+	case "pgdown", "f", " ":
+		m.statsView.PageDown()
+	case "g", "home":
+		m.statsView.GotoTop()
+	case "G", "end":
+		m.statsView.GotoBottom()
+	default:
+		return nil, nil
+	}
+	m.message = ""
+	return &m, nil
+}
+
+// statsProject names what the stats screen is summarizing.
+func (m Model) statsProject() string {
+	if m.selectedProject != "" {
+		return m.selectedProject
+	}
+	if len(m.logFiles) > 0 {
+		return fileSource(m.logFiles[0]).Name
+	}
+	return filepath.Base(m.projectPath)
+}
+
+// formatStats renders each section of the report as lines of the stats
+// viewport.
+func (m Model) formatStats(report *stats.Report) []string {
+	width := m.statsView.width
+	if width <= 0 {
+		width = 80
+	}
+	heading := lipgloss.NewStyle().Bold(true).Foreground(m.theme().Accent)
+	muted := lipgloss.NewStyle().Foreground(m.theme().Muted)
+	text := lipgloss.NewStyle().Foreground(m.theme().Text)
+
+	var size int64
+	for _, file := range report.Files {
+		size += file.Size
+	}
+	summary := fmt.Sprintf("%d files  •  %s  •  %d entries", len(report.Files), formatSize(size), report.Entries)
+	if !report.First.IsZero() {
+		summary += fmt.Sprintf("  •  %s – %s", report.First.Format("Jan 02 15:04"), report.Last.Format("Jan 02 15:04"))
+	}
+	lines := []string{text.Render(summary), ""}
+
+	var totals []string
+	for _, level := range stats.Levels {
+		totals = append(totals, lipgloss.NewStyle().
+			Foreground(m.theme().levelColor(level)).
+			Render(fmt.Sprintf("%s %d (%s)", level, report.Levels[level], percent(report.Levels[level], report.Entries))))
+	}
+	lines = append(lines, strings.Join(totals, "   "), "")
+
+	anomalyStyle := lipgloss.NewStyle().Foreground(m.theme().Error).Bold(true)
+	anomalies := report.Anomalies(time.Now())
+	if len(anomalies) > 0 {
+		lines = append(lines, heading.Render(fmt.Sprintf("Anomalies in the last 24h (vs. the %d days before)", stats.BaselineDays)))
+		for _, a := range anomalies {
+			lines = append(lines, anomalyStyle.Render(cell("⚠ "+formatAnomaly(a), width)))
+		}
+		lines = append(lines, "")
+	}
+
+	hours := report.Timeline()
+	if len(hours) > 0 {
+		// One column per hour, or per several hours when the range is wider
+		// than the screen.
+		chartWidth := max(10, width-statsLabelWidth-10)
+		per := (len(hours) + chartWidth - 1) / chartWidth
+		columns := (len(hours) + per - 1) / per
+		bucket := "per hour"
+		if per > 1 {
+			bucket = fmt.Sprintf("per %d hours", per)
+		}
+
+		lines = append(lines, heading.Render("Entries per level over time ("+bucket+")"))
+		errors := make([]float64, columns)
+		totalsPer := make([]float64, columns)
+		for _, level := range stats.Levels {
+			values := make([]float64, columns)
+			for i, hour := range hours {
+				values[i/per] += float64(hour.Levels[level])
+				if level == stats.Levels[0] {
+					errors[i/per] += float64(hour.Levels[level])
+					totalsPer[i/per] += float64(hour.Total)
+				}
+			}
+			peak := 0.0
+			for _, v := range values {
+				peak = max(peak, v)
+			}
+			lines = append(lines, lipgloss.NewStyle().
+				Foreground(m.theme().levelColor(level)).
+				Render(fmt.Sprintf("%-*s%s", statsLabelWidth, level, sparkline(values)))+
+				muted.Render(fmt.Sprintf(" max %.0f", peak)))
+		}
+		lines = append(lines, muted.Render(timeAxis(hours, statsLabelWidth, columns)), "")
+
+		// Error rate is errors over all entries in each column.
+		rates := make([]float64, columns)
+		peakAt, peakRate := 0, 0.0
+		for i := range rates {
+			if totalsPer[i] > 0 {
+				rates[i] = errors[i] * 100 / totalsPer[i]
+			}
+			if rates[i] > peakRate {
+				peakAt, peakRate = i, rates[i]
+			}
+		}
+		lines = append(lines, heading.Render("Error rate ("+bucket+")"))
+		lines = append(lines, lipgloss.NewStyle().
+			Foreground(m.theme().Error).
+			Render(fmt.Sprintf("%-*s%s", statsLabelWidth, "ERROR%", sparkline(rates))))
+		if len(anomalies) > 0 {
+			// Mark the columns holding an anomalous hour under the chart.
+			markers := []rune(strings.Repeat(" ", columns))
+			for _, a := range anomalies {
+				if i := int(a.Hour.Sub(hours[0].Start) / time.Hour); i >= 0 && i < len(hours) {
+					markers[i/per] = '▲'
+				}
+			}
+			lines = append(lines, anomalyStyle.Render(strings.Repeat(" ", statsLabelWidth)+string(markers)))
+		}
+		lines = append(lines, muted.Render(timeAxis(hours, statsLabelWidth, columns)))
+		lines = append(lines, text.Render(fmt.Sprintf("Overall %s, peak %.1f%% at %s",
+			percent(report.Levels[stats.Levels[0]], report.Entries), peakRate,
+			hours[peakAt*per].Start.Format("Jan 02 15:04"))), "")
+
+		lines = append(lines, heading.Render("Busiest hours"))
+		busiest := report.BusiestHours(busiestHourCount)
+		for _, hour := range busiest {
+			errorCount := hour.Levels[stats.Levels[0]]
+			lines = append(lines, text.Render(fmt.Sprintf("%s  %s %d entries", hour.Start.Format("Jan 02 15:00"),
+				bar(hour.Total, busiest[0].Total, statsBarWidth), hour.Total))+
+				muted.Render(fmt.Sprintf(", %d errors (%s)", errorCount, percent(errorCount, hour.Total))))
+		}
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, heading.Render("Top messages"))
+	for _, count := range report.TopMessages(topMessageCount) {
+		message := strings.Join(strings.Fields(count.Value), " ")
+		if message == "" {
+			message = "(no message)"
+		}
+		lines = append(lines, text.Render(cell(fmt.Sprintf("%7d  %s", count.Count, message), width)))
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, heading.Render("Files"))
+	nameWidth := 0
+	var largest int64
+	for _, file := range report.Files {
+		nameWidth = max(nameWidth, len(filepath.Base(file.Path)))
+		largest = max(largest, file.Size)
+	}
+	for _, file := range report.Files {
+		lines = append(lines, text.Render(fmt.Sprintf("%-*s  %s %9s  %d entries", nameWidth, filepath.Base(file.Path),
+			bar(int(file.Size), int(largest), statsBarWidth), formatSize(file.Size), file.Entries)))
+	}
+	return lines
+}
+
+// sparkline draws values as block glyphs scaled to the largest; zero is a
+// blank so quiet stretches stand out.
+func sparkline(values []float64) string {
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		if v <= 0 || peak == 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		level := int(v / peak * float64(len(sparkBlocks)-1))
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// timeAxis labels the first and last hour under a chart that starts after
+// indent columns and spans the given number of columns.
+func timeAxis(hours []stats.Hour, indent, columns int) string {
+	start := hours[0].Start.Format("Jan 02 15:04")
+	end := hours[len(hours)-1].Start.Format("Jan 02 15:04")
+	gap := columns - len(start) - len(end)
+	if gap < 1 {
+		return strings.Repeat(" ", indent) + start
+	}
+	return strings.Repeat(" ", indent) + start + strings.Repeat(" ", gap) + end
+}
+
+// bar draws value as a horizontal bar, width wide at peak.
+func bar(value, peak, width int) string {
+	filled := 0
+	if peak > 0 {
+		filled = value * width / peak
+	}
+	if value > 0 && filled == 0 {
+		filled = 1
+	}
+	return strings.Repeat("█", filled) + strings.Repeat(" ", width-filled)
+}
+
+func percent(n, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(n)*100/float64(total))
+}
+
+// formatSize renders a byte count in the largest unit that keeps it above 1.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func (m Model) renderStats() string {
+	return m.renderReport("📊 Stats: " + m.statsProject())
+}
+
+// renderReport draws a screen that scrolls through the lines of a report,
+// or the progress of reading it.
+func (m Model) renderReport(title string) string {
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme().Accent).
+		Render(title)
+
+	var body, footer string
+	if m.statsReport == nil || m.numbersPending {
+		body = m.renderStatsProgress()
+		footer = "Reading log files...  •  ESC back"
+	} else {
+		visible, _ := m.statsView.VisibleLines()
+		lines := append([]string(nil), visible...)
+		for len(lines) < m.statsView.visibleHeight() {
+			lines = append(lines, "")
+		}
+		body = strings.Join(lines, "\n")
+		first, last, total, pct := m.statsView.Position()
+		footer = fmt.Sprintf("lines %d-%d of %d (%d%%)", first, last, total, pct) +
+			"  •  j/k PgUp/PgDn scroll  r refresh  ESC back"
+		if m.screen == screenNumbers {
+			footer = fmt.Sprintf("lines %d-%d of %d (%d%%)", first, last, total, pct) +
+				"  •  j/k PgUp/PgDn scroll  a analyze a field  r refresh  ESC back"
+		}
+	}
+	if m.analyzing {
+		footer = "analyze: " + m.editValue + "█  •  field or \"field by key\", e.g. duration_ms by path, ENTER run, ESC cancel"
+	}
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme().Muted).
+		Render(m.fitWidth(footer))
+
+	messageStr := "\n" + lipgloss.NewStyle().
+		Foreground(m.theme().Message).
+		Render(m.fitWidth(m.message))
+
+	return fmt.Sprintf("%s\n\n%s\n\n%s%s", header, body, instructions, messageStr)
+}
+
+// renderStatsProgress is the progress bar shown while files are read.
+func (m Model) renderStatsProgress() string {
+	fraction := 0.0
+	if m.statsTotal > 0 {
+		fraction = max(0, min(1, float64(m.statsDone)/float64(m.statsTotal)))
+	}
+	width := 40
+	filled := int(fraction * float64(width))
+	progress := lipgloss.NewStyle().Foreground(m.theme().Accent).Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(m.theme().Muted).Render(strings.Repeat("░", width-filled))
+	return fmt.Sprintf("%s %3.0f%%  %s of %s", progress, fraction*100, formatSize(m.statsDone), formatSize(m.statsTotal))
+}
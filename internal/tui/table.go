@@ -0,0 +1,428 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/LFroesch/logdog/internal/detector"
+	"github.com/LFroesch/logdog/internal/logs"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	tableTimeWidth  = 15 // "Jan 02 15:04:05"
+	tableLevelWidth = 7  // "LEVEL ▲"
+	// tableAutoWidth caps the width of data columns sized to their content.
+	tableAutoWidth = 24
+	// tableMessageWidth is the narrowest the message column gets.
+	tableMessageWidth = 10
+	tableGap          = "  "
+)
+
+// Sort keys for the built-in columns. Anything else is a data key.
+const (
+	sortTime    = ""
+	sortLevel   = "level"
+	sortMessage = "message"
+)
+
+// tableColumn is a data key promoted to a table column. Width 0 sizes the
+// column to its content.
+type tableColumn struct {
+	Key   string
+	Width int
+}
+
+func (c tableColumn) String() string {
+	if c.Width > 0 {
+		return fmt.Sprintf("%s:%d", c.Key, c.Width)
+	}
+	return c.Key
+}
+
+// parseTableColumns parses a column list such as "method path:30 status",
+// separated by spaces or commas.
+func parseTableColumns(input string) ([]tableColumn, error) {
+	var columns []tableColumn
+	seen := map[string]bool{}
+	for _, term := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		column := tableColumn{Key: term}
+		if key, width, ok := strings.Cut(term, ":"); ok {
+			n, err := strconv.Atoi(width)
+			if err != nil || n < 1 || key == "" {
+				return nil, fmt.Errorf("invalid column %q, expected key or key:width", term)
+			}
+			column = tableColumn{Key: key, Width: n}
+		}
+		if seen[column.Key] {
+			continue
+		}
+		seen[column.Key] = true
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// viewerProject is the name of the project whose logs are being viewed,
+// which is the name of their directory, or the names joined with "+" for
+// several projects.
+func (m Model) viewerProject() string {
+	names := make([]string, len(m.viewSources))
+	for i, source := range m.viewSources {
+		names[i] = source.Name
+	}
+	return strings.Join(names, "+")
+}
+
+// loadTableSettings restores the table preferences saved for the project
+// being viewed.
+func (m *Model) loadTableSettings() {
+	saved := m.global.Projects[m.viewerProject()]
+	m.tableMode = saved.TableView
+	m.tableColumns, _ = parseTableColumns(strings.Join(saved.TableColumns, " "))
+	m.sortColumn = sortTime
+	m.sortDesc = false
+}
+
+// saveTableSettings remembers the table preferences for the project being
+// viewed in the global config.
+func (m *Model) saveTableSettings() error {
+	var columns []string
+	for _, c := range m.tableColumns {
+		columns = append(columns, c.String())
+	}
+
+	projects := make(map[string]detector.ProjectSettings, len(m.global.Projects)+1)
+	for name, settings := range m.global.Projects {
+		projects[name] = settings
+	}
+	settings := projects[m.viewerProject()]
+	settings.TableView = m.tableMode
+	settings.TableColumns = columns
+	projects[m.viewerProject()] = settings
+	m.global.Projects = projects
+
+	return detector.SaveGlobalConfig(m.global)
+}
+
+func (m Model) toggleTable() (Model, tea.Cmd) {
+	m.tableMode = !m.tableMode
+	if !m.tableMode {
+		m.sortColumn = sortTime
+		m.sortDesc = false
+	}
+	m.resizeViewer()
+	m.applyFilters()
+
+	if err := m.saveTableSettings(); err != nil {
+		m.message = fmt.Sprintf("❌ Failed to save table settings: %v", err)
+	} else if m.tableMode {
+		m.message = "Table view, press c to choose columns"
+	} else {
+		m.message = "List view"
+	}
+	return m, nil
+}
+
+// startColumns opens the column prompt, pre-filled with the current columns.
+func (m Model) startColumns() (Model, tea.Cmd) {
+	m.editingColumns = true
+	var terms []string
+	for _, c := range m.tableColumns {
+		terms = append(terms, c.String())
+	}
+	return m.startEdit(strings.Join(terms, " "))
+}
+
+func (m Model) commitColumns(input string) (Model, tea.Cmd) {
+	m.editingColumns = false
+	columns, err := parseTableColumns(input)
+	if err != nil {
+		m.message = fmt.Sprintf("❌ %v", err)
+		return m, nil
+	}
+
+	m.tableColumns = columns
+	if !m.hasSortColumn(m.sortColumn) {
+		m.sortColumn = sortTime
+		m.sortDesc = false
+	}
+	if !m.tableMode {
+		m.tableMode = true
+		m.resizeViewer()
+	}
+	m.applyFilters()
+
+	if err := m.saveTableSettings(); err != nil {
+		m.message = fmt.Sprintf("❌ Failed to save table settings: %v", err)
+	} else {
+		m.message = fmt.Sprintf("✅ Columns saved for %s", m.viewerProject())
+	}
+	return m, nil
+}
+
+// sortKeys lists what the table can be sorted by, in the order s cycles
+// through them.
+func (m Model) sortKeys() []string {
+	keys := []string{sortTime, sortLevel, sortMessage}
+	for _, c := range m.tableColumns {
+		keys = append(keys, c.Key)
+	}
+	return keys
+}
+
+func (m Model) hasSortColumn(key string) bool {
+	for _, k := range m.sortKeys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// cycleSort moves the sort to the next column, or reverses the direction.
+func (m Model) cycleSort(reverse bool) (Model, tea.Cmd) {
+	if !m.tableMode {
+		m.message = "Sorting is only available in the table view, press t"
+		return m, nil
+	}
+
+	if reverse {
+		m.sortDesc = !m.sortDesc
+	} else {
+		keys := m.sortKeys()
+		next := 0
+		for i, k := range keys {
+			if k == m.sortColumn {
+				next = (i + 1) % len(keys)
+				break
+			}
+		}
+		m.sortColumn = keys[next]
+		m.sortDesc = false
+	}
+	m.applyFilters()
+	m.viewer.GotoTop()
+	m.entryCursor = 0
+
+	name := m.sortColumn
+	if name == sortTime {
+		name = "time"
+	}
+	direction := "ascending"
+	if m.sortDesc {
+		direction = "descending"
+	}
+	m.message = fmt.Sprintf("Sorted by %s, %s", name, direction)
+	return m, nil
+}
+
+// sorted reports whether the viewer's entries are in some order other than
+// the file's.
+func (m Model) sorted() bool {
+	return m.tableMode && (m.sortColumn != sortTime || m.sortDesc)
+}
+
+// sortEntries orders viewEntries (and viewSource with them) by the sort
+// column. It returns, for each old position, the entry's new position.
+func (m *Model) sortEntries() []int {
+	order := make([]int, len(m.viewEntries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ea, eb := m.viewEntries[order[a]], m.viewEntries[order[b]]
+		if m.sortDesc {
+			return compareEntries(eb, ea, m.sortColumn) < 0
+		}
+		return compareEntries(ea, eb, m.sortColumn) < 0
+	})
+
+	entries := make([]logs.Entry, len(order))
+	source := make([]int, len(order))
+	moved := make([]int, len(order))
+	for to, from := range order {
+		entries[to] = m.viewEntries[from]
+		source[to] = m.viewSource[from]
+		moved[from] = to
+	}
+	m.viewEntries = entries
+	m.viewSource = source
+	return moved
+}
+
+// compareEntries compares two entries by a sort key. Numbers compare as
+// numbers, and entries missing a data key sort before those that have it.
+func compareEntries(a, b logs.Entry, key string) int {
+	switch key {
+	case sortTime:
+		return a.Time.Compare(b.Time)
+	case sortLevel:
+		return levelRank(a.Level) - levelRank(b.Level)
+	case sortMessage:
+		return strings.Compare(a.Message, b.Message)
+	}
+
+	va, okA := a.DataValue(key)
+	vb, okB := b.DataValue(key)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+
+	na, numA := va.(float64)
+	nb, numB := vb.(float64)
+	if numA && numB {
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(logs.FormatValue(va), logs.FormatValue(vb))
+}
+
+// tableLayout works out the width of every data column and the message
+// column for the current entries and viewer width.
+func (m Model) tableLayout() (widths []int, messageWidth int) {
+	used := tableTimeWidth + len(tableGap) + tableLevelWidth + len(tableGap)
+	if width := m.sourceWidth(); width > 0 {
+		used += width + len(tableGap)
+	}
+	if m.activeTrace != nil {
+		used += traceStepWidth + 1
+	}
+	for _, c := range m.tableColumns {
+		width := c.Width
+		if width == 0 {
+			// Leave room for the sort marker after the title.
+			width = lipgloss.Width(c.Key) + 2
+			for _, entry := range m.viewEntries {
+				if v, ok := entry.DataValue(c.Key); ok {
+					width = max(width, lipgloss.Width(cellText(v)))
+				}
+				if width >= tableAutoWidth {
+					width = tableAutoWidth
+					break
+				}
+			}
+		}
+		widths = append(widths, width)
+		used += width + len(tableGap)
+	}
+	return widths, max(tableMessageWidth, m.viewer.visibleWidth()-used)
+}
+
+// sourceWidth is the width of the project column, which the table only
+// has when it mixes several projects.
+func (m Model) sourceWidth() int {
+	if !m.multiSource() {
+		return 0
+	}
+	width := lipgloss.Width("PROJECT")
+	for _, source := range m.viewSources {
+		width = max(width, lipgloss.Width(source.Name))
+	}
+	return width
+}
+
+// cellText renders a data value on a single line.
+func cellText(v any) string {
+	return strings.Join(strings.Fields(logs.FormatValue(v)), " ")
+}
+
+// cell truncates or pads text to exactly width columns.
+func cell(text string, width int) string {
+	if lipgloss.Width(text) > width {
+		runes := []rune(text)
+		for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+			runes = runes[:len(runes)-1]
+		}
+		text = string(runes) + "…"
+	}
+	return text + strings.Repeat(" ", max(0, width-lipgloss.Width(text)))
+}
+
+func (m Model) formatTableRow(entry logs.Entry, widths []int, messageWidth int) string {
+	if !entry.Valid {
+		return m.highlight(cell(entry.Raw, m.viewer.visibleWidth()), lipgloss.NewStyle())
+	}
+
+	muted := lipgloss.NewStyle().Foreground(m.theme().Muted)
+	dataStyle := lipgloss.NewStyle().Foreground(m.theme().Accent)
+
+	timestamp := entry.Timestamp
+	if !entry.Time.IsZero() {
+		timestamp = entry.Time.Format("Jan 02 15:04:05")
+	}
+
+	var row strings.Builder
+	row.WriteString(muted.Render(cell(timestamp, tableTimeWidth)) + tableGap)
+	if width := m.sourceWidth(); width > 0 {
+		row.WriteString(m.sourceStyle(entry.Source).Render(cell(entry.Source, width)) + tableGap)
+	}
+	row.WriteString(lipgloss.NewStyle().
+		Foreground(m.theme().levelColor(entry.Level)).
+		Bold(true).
+		Render(cell(entry.Level, tableLevelWidth)) + tableGap)
+	for i, c := range m.tableColumns {
+		text := ""
+		if v, ok := entry.DataValue(c.Key); ok {
+			text = cellText(v)
+		}
+		row.WriteString(m.highlight(cell(text, widths[i]), dataStyle) + tableGap)
+	}
+	message := strings.Join(strings.Fields(entry.Message), " ")
+	row.WriteString(m.highlight(cell(message, messageWidth), lipgloss.NewStyle().
+		Foreground(m.theme().Text)))
+
+	return lipgloss.NewStyle().MaxWidth(m.viewer.visibleWidth()).Render(row.String())
+}
+
+// renderTableHeader renders the column titles, marking the sort column.
+func (m Model) renderTableHeader() string {
+	widths, messageWidth := m.tableLayout()
+
+	title := func(name, key string, width int) string {
+		if key != m.sortColumn || !m.sorted() {
+			return cell(name, width)
+		}
+		marker := " ▲"
+		if m.sortDesc {
+			marker = " ▼"
+		}
+		if width <= 2 {
+			return cell(marker, width)
+		}
+		return cell(strings.TrimRight(cell(name, width-2), " ")+marker, width)
+	}
+
+	var header strings.Builder
+	if m.activeTrace != nil {
+		header.WriteString(fmt.Sprintf("%*s ", traceStepWidth, "STEP"))
+	}
+	header.WriteString(title("TIME", sortTime, tableTimeWidth) + tableGap)
+	if width := m.sourceWidth(); width > 0 {
+		header.WriteString(cell("PROJECT", width) + tableGap)
+	}
+	header.WriteString(title("LEVEL", sortLevel, tableLevelWidth) + tableGap)
+	for i, c := range m.tableColumns {
+		header.WriteString(title(c.Key, c.Key, widths[i]) + tableGap)
+	}
+	header.WriteString(title("MESSAGE", sortMessage, messageWidth))
+
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme().Accent).
+		MaxWidth(m.viewer.visibleWidth()).
+		Render(header.String())
+}
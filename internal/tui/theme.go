@@ -0,0 +1,85 @@
+package tui
+
+import "github.com/charmbracelet/lipgloss"
+
+type theme struct {
+	Accent     lipgloss.Color
+	SelectedBg lipgloss.Color
+	SelectedFg lipgloss.Color
+	Text       lipgloss.Color
+	Muted      lipgloss.Color
+	Message    lipgloss.Color
+	Error      lipgloss.Color
+	Warn       lipgloss.Color
+	Info       lipgloss.Color
+	Debug      lipgloss.Color
+	// Projects tell projects apart in cross-project views.
+	Projects []lipgloss.Color
+}
+
+// themeNames lists the available themes in the order settings cycles them.
+var themeNames = []string{"default", "light", "mono"}
+
+var themes = map[string]theme{
+	"default": {
+		Accent:     lipgloss.Color("99"),
+		SelectedBg: lipgloss.Color("57"),
+		SelectedFg: lipgloss.Color("230"),
+		Text:       lipgloss.Color("252"),
+		Muted:      lipgloss.Color("240"),
+		Message:    lipgloss.Color("226"),
+		Error:      lipgloss.Color("196"),
+		Warn:       lipgloss.Color("208"),
+		Info:       lipgloss.Color("46"),
+		Debug:      lipgloss.Color("240"),
+		Projects:   []lipgloss.Color{"39", "213", "220", "42", "141", "209", "51", "168"},
+	},
+	"light": {
+		Accent:     lipgloss.Color("55"),
+		SelectedBg: lipgloss.Color("189"),
+		SelectedFg: lipgloss.Color("16"),
+		Text:       lipgloss.Color("235"),
+		Muted:      lipgloss.Color("244"),
+		Message:    lipgloss.Color("130"),
+		Error:      lipgloss.Color("160"),
+		Warn:       lipgloss.Color("166"),
+		Info:       lipgloss.Color("28"),
+		Debug:      lipgloss.Color("244"),
+		Projects:   []lipgloss.Color{"25", "127", "136", "29", "91", "166", "30", "125"},
+	},
+	"mono": {
+		Accent:     lipgloss.Color("15"),
+		SelectedBg: lipgloss.Color("250"),
+		SelectedFg: lipgloss.Color("16"),
+		Text:       lipgloss.Color("252"),
+		Muted:      lipgloss.Color("245"),
+		Message:    lipgloss.Color("15"),
+		Error:      lipgloss.Color("15"),
+		Warn:       lipgloss.Color("252"),
+		Info:       lipgloss.Color("250"),
+		Debug:      lipgloss.Color("245"),
+		Projects:   []lipgloss.Color{"15", "250", "245", "252", "248", "242"},
+	},
+}
+
+func (m Model) theme() theme {
+	if t, ok := themes[m.global.Theme]; ok {
+		return t
+	}
+	return themes["default"]
+}
+
+func (t theme) levelColor(level string) lipgloss.Color {
+	switch level {
+	case "ERROR":
+		return t.Error
+	case "WARN":
+		return t.Warn
+	case "INFO":
+		return t.Info
+	case "DEBUG":
+		return t.Debug
+	default:
+		return t.Text
+	}
+}
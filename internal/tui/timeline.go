@@ -0,0 +1,262 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/detector"
+	"github.com/LFroesch/logdog/internal/logs"
+	"github.com/LFroesch/logdog/internal/query"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// minTimelineEntries is how many entries the timeline loads when it opens
+// or jumps, so there is something to scroll through, days permitting.
+const minTimelineEntries = 500
+
+// openTimeline opens the viewer on the merged timeline of the sources,
+// starting at the most recent entries.
+func (m Model) openTimeline(sources ...logs.Source) (Model, tea.Cmd) {
+	timeline, err := logs.NewTimeline(sources...)
+	if err != nil {
+		m.message = fmt.Sprintf("❌ Error reading logs: %v", err)
+		return m, nil
+	}
+	if len(timeline.Days()) == 0 {
+		m.message = "No daily log files found"
+		return m, nil
+	}
+
+	m.timeline = timeline
+	m.viewingFile = ""
+	m.viewSources = sources
+	m.activeQuery = nil
+	m.activeTrace = nil
+	m.activeGroup = nil
+	return m.loadTimelineAt(len(timeline.Days())-1, time.Time{})
+}
+
+// loadTimelineAt replaces the loaded days with the given day and puts the
+// cursor on the first entry at or after at, or on the last entry if at is
+// zero.
+func (m Model) loadTimelineAt(day int, at time.Time) (Model, tea.Cmd) {
+	entries, err := m.timeline.ReadDay(day)
+	if err != nil {
+		m.message = fmt.Sprintf("❌ Error reading logs: %v", err)
+		return m, nil
+	}
+	m.timelineFirst, m.timelineLast = day, day
+	m.showEntries(entries)
+
+	for len(m.allEntries) < minTimelineEntries && m.timelineFirst > 0 {
+		if err := m.prependDay(); err != nil {
+			m.message = fmt.Sprintf("❌ Error reading logs: %v", err)
+			break
+		}
+	}
+
+	if at.IsZero() {
+		m.viewer.GotoBottom()
+		m.entryCursor = max(0, len(m.viewEntries)-1)
+		return m, nil
+	}
+
+	for {
+		for i, entry := range m.viewEntries {
+			if !entry.Time.Before(at) {
+				m.entryCursor = i
+				m.viewer.ScrollToItem(i)
+				return m, nil
+			}
+		}
+		if m.timelineLast >= len(m.timeline.Days())-1 {
+			break
+		}
+		if err := m.appendDay(); err != nil {
+			m.message = fmt.Sprintf("❌ Error reading logs: %v", err)
+			break
+		}
+	}
+	m.viewer.GotoBottom()
+	m.entryCursor = max(0, len(m.viewEntries)-1)
+	return m, nil
+}
+
+// prependDay loads the day before the loaded ones, keeping the view where
+// it is.
+func (m *Model) prependDay() error {
+	entries, err := m.timeline.ReadDay(m.timelineFirst - 1)
+	if err != nil {
+		return err
+	}
+	m.timelineFirst--
+
+	m.allEntries = append(entries, m.allEntries...)
+	for i := range m.viewSource {
+		m.viewSource[i] += len(entries)
+	}
+	m.applyFilters()
+	return nil
+}
+
+// appendDay loads the day after the loaded ones.
+func (m *Model) appendDay() error {
+	entries, err := m.timeline.ReadDay(m.timelineLast + 1)
+	if err != nil {
+		return err
+	}
+	m.timelineLast++
+
+	m.allEntries = append(m.allEntries, entries...)
+	m.applyFilters()
+	return nil
+}
+
+// extendTimeline loads the neighbouring day once the cursor reaches either
+// end of what is loaded, so paging carries on across file boundaries.
+func (m *Model) extendTimeline() {
+	if m.timeline == nil {
+		return
+	}
+
+	var err error
+	switch {
+	case m.viewer.AtBottom() && m.entryCursor >= len(m.viewEntries)-1 &&
+		m.timelineLast < len(m.timeline.Days())-1:
+		err = m.appendDay()
+	case m.viewer.offset == 0 && m.entryCursor == 0 && m.timelineFirst > 0:
+		err = m.prependDay()
+	}
+	if err != nil {
+		m.message = fmt.Sprintf("❌ Error reading logs: %v", err)
+	}
+}
+
+// startJump opens the prompt for a date or time to jump to.
+func (m Model) startJump() (Model, tea.Cmd) {
+	m.jumping = true
+	return m.startEdit("")
+}
+
+// commitJump moves the cursor to the first entry at or after the given
+// time, loading that day first in the timeline.
+func (m Model) commitJump(input string) (Model, tea.Cmd) {
+	m.jumping = false
+	at, err := query.ParseTime(strings.Trim(input, `" `), time.Now())
+	if err != nil {
+		m.message = fmt.Sprintf("❌ %v", err)
+		return m, nil
+	}
+
+	if m.timeline != nil {
+		m, cmd := m.loadTimelineAt(m.timeline.DayIndex(at), at)
+		if m.message == "" {
+			m.message = "Jumped to " + at.Format("Jan 02 15:04:05")
+		}
+		return m, cmd
+	}
+
+	for i, entry := range m.viewEntries {
+		if !entry.Time.Before(at) {
+			m.entryCursor = i
+			m.viewer.ScrollToItem(i)
+			m.message = "Jumped to " + at.Format("Jan 02 15:04:05")
+			return m, nil
+		}
+	}
+	m.message = "No entries after " + at.Format("Jan 02 15:04:05")
+	return m, nil
+}
+
+// toggleProjectMark marks or unmarks the project under the cursor for a
+// merged timeline.
+func (m Model) toggleProjectMark() (Model, tea.Cmd) {
+	if m.cursor >= len(m.globalProjects) {
+		return m, nil
+	}
+	project := m.globalProjects[m.cursor]
+
+	marked := make(map[string]bool, len(m.markedProjects)+1)
+	for name := range m.markedProjects {
+		marked[name] = true
+	}
+	if marked[project] {
+		delete(marked, project)
+	} else {
+		marked[project] = true
+	}
+	m.markedProjects = marked
+	m.message = fmt.Sprintf("%d projects marked", len(marked))
+	return m, nil
+}
+
+// openMergedTimeline opens one timeline interleaving the marked projects.
+func (m Model) openMergedTimeline() (Model, tea.Cmd) {
+	if len(m.markedProjects) == 0 {
+		m.message = "Mark projects with SPACE first"
+		return m, nil
+	}
+
+	root, err := detector.LogRoot()
+	if err != nil {
+		m.message = fmt.Sprintf("❌ %v", err)
+		return m, nil
+	}
+	var sources []logs.Source
+	for _, project := range m.globalProjects {
+		if m.markedProjects[project] {
+			sources = append(sources, logs.Source{Name: project, Dir: filepath.Join(root, project)})
+		}
+	}
+	return m.openTimeline(sources...)
+}
+
+// multiSource reports whether the viewer mixes entries from several
+// projects, in which case each entry is tagged with its project.
+func (m Model) multiSource() bool {
+	return len(m.viewSources) > 1
+}
+
+// sourceStyle colors a project's name, each project in the view getting
+// its own color.
+func (m Model) sourceStyle(name string) lipgloss.Style {
+	palette := m.theme().Projects
+	for i, source := range m.viewSources {
+		if source.Name == name {
+			return lipgloss.NewStyle().Foreground(palette[i%len(palette)])
+		}
+	}
+	return lipgloss.NewStyle().Foreground(m.theme().Text)
+}
+
+// sourceNames lists the projects in the view, each in its color.
+func (m Model) sourceNames() string {
+	names := make([]string, len(m.viewSources))
+	for i, source := range m.viewSources {
+		names[i] = source.Name
+		if m.multiSource() {
+			names[i] = m.sourceStyle(source.Name).Render(source.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// timelineTitle is the viewer header for the timeline: the projects and
+// the range of days loaded so far.
+func (m Model) timelineTitle() string {
+	days := m.timeline.Days()
+	name := m.sourceNames()
+	return fmt.Sprintf("🕒 Timeline: %s  %s – %s  (%d of %d days loaded)",
+		name,
+		days[m.timelineFirst].Format("Jan 02 2006"),
+		days[m.timelineLast].Format("Jan 02 2006"),
+		m.timelineLast-m.timelineFirst+1, len(days))
+}
+
+// jumpStatus is the footer line while the jump prompt is open.
+func (m Model) jumpStatus() string {
+	return "jump to: " + m.editValue + "█  •  e.g. 2024-01-15, 2024-01-15 23:50, 14:30, yesterday, 2h (ago), ENTER jump, ESC cancel"
+}
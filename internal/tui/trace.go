@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/detector"
+	"github.com/LFroesch/logdog/internal/logs"
+	"github.com/LFroesch/logdog/internal/query"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// traceStepWidth is the width of the step duration column of a trace.
+const traceStepWidth = 9
+
+// trace is a request or trace id being followed across projects.
+type trace struct {
+	key string
+	id  string
+}
+
+// traceKey returns the first correlation key the entry has a value for.
+func (m Model) traceKey(entry logs.Entry) (trace, bool) {
+	for _, key := range m.global.CorrelationKeys {
+		if v, ok := entry.DataValue(key); ok {
+			if id := logs.FormatValue(v); id != "" {
+				return trace{key: key, id: id}, true
+			}
+		}
+	}
+	return trace{}, false
+}
+
+// startTrace finds every entry sharing the selected entry's correlation id,
+// across all projects under the log root as well as the ones being viewed.
+func (m Model) startTrace() (Model, tea.Cmd) {
+	if m.entryCursor >= len(m.viewEntries) {
+		return m, nil
+	}
+	t, ok := m.traceKey(m.viewEntries[m.entryCursor])
+	if !ok {
+		m.message = fmt.Sprintf("This entry has no %s", strings.Join(m.global.CorrelationKeys, ", "))
+		return m, nil
+	}
+
+	q, err := query.Parse(fmt.Sprintf("data.%s=%s", t.key, strconv.Quote(t.id)), time.Now())
+	if err != nil {
+		m.message = fmt.Sprintf("❌ Invalid trace key %q: %v", t.key, err)
+		return m, nil
+	}
+
+	if m.activeQuery == nil {
+		m.queryReturn = m.viewingFile
+	}
+	m.detailOpen = false
+	m.message = fmt.Sprintf("Tracing %s=%s...", t.key, t.id)
+	run := runQuery(q, m.traceSources())
+	return m, func() tea.Msg {
+		msg := run().(queryDoneMsg)
+		msg.trace = &t
+		return msg
+	}
+}
+
+// traceSources are every project under the log root, plus any directory
+// being viewed that lives elsewhere.
+func (m Model) traceSources() []logs.Source {
+	var sources []logs.Source
+	seen := map[string]bool{}
+	if root, err := detector.LogRoot(); err == nil {
+		for _, project := range scanGlobalProjects() {
+			dir := filepath.Join(root, project)
+			sources = append(sources, logs.Source{Name: project, Dir: dir})
+			seen[dir] = true
+		}
+	}
+	for _, source := range m.viewSources {
+		if !seen[source.Dir] {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// traceSpan is the time from the earliest to the latest entry of the trace.
+func (m Model) traceSpan() time.Duration {
+	var first, last time.Time
+	for _, entry := range m.allEntries {
+		if entry.Time.IsZero() {
+			continue
+		}
+		if first.IsZero() || entry.Time.Before(first) {
+			first = entry.Time
+		}
+		if entry.Time.After(last) {
+			last = entry.Time
+		}
+	}
+	return last.Sub(first)
+}
+
+// traceTitle is the viewer header while showing a trace.
+func (m Model) traceTitle() string {
+	return fmt.Sprintf("🔗 %s=%s  across %s  (%d entries in %s)", m.activeTrace.key, m.activeTrace.id,
+		m.viewerProject(), len(m.allEntries), formatStep(m.traceSpan()))
+}
+
+// traceSteps renders, for each of viewEntries, the time since the entry
+// before it in time order, so the slow steps of a request stand out however
+// the view is sorted. The earliest entry and entries without a time are the
+// "start".
+func (m Model) traceSteps() []string {
+	var dated []int
+	for i, entry := range m.viewEntries {
+		if !entry.Time.IsZero() {
+			dated = append(dated, i)
+		}
+	}
+	sort.SliceStable(dated, func(a, b int) bool {
+		return m.viewEntries[dated[a]].Time.Before(m.viewEntries[dated[b]].Time)
+	})
+
+	muted := lipgloss.NewStyle().Foreground(m.theme().Muted)
+	slow := lipgloss.NewStyle().Foreground(m.theme().levelColor("WARN")).Bold(true)
+	steps := make([]string, len(m.viewEntries))
+	for i := range steps {
+		steps[i] = muted.Render(fmt.Sprintf("%*s", traceStepWidth, "start"))
+	}
+	for k := 1; k < len(dated); k++ {
+		step := m.viewEntries[dated[k]].Time.Sub(m.viewEntries[dated[k-1]].Time)
+		style := muted
+		if step >= time.Second {
+			style = slow
+		}
+		steps[dated[k]] = style.Render(fmt.Sprintf("%*s", traceStepWidth, "+"+formatStep(step)))
+	}
+	return steps
+}
+
+// formatStep renders a duration compactly: milliseconds under a second,
+// then seconds, then minutes and seconds.
+func formatStep(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	default:
+		return d.Round(time.Second).String()
+	}
+}
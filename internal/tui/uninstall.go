@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LFroesch/logdog/internal/detector"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (m Model) toggleUninstallOption() (Model, tea.Cmd) {
+	switch m.cursor {
+	case 0:
+		m.uninstallRewrite = !m.uninstallRewrite
+	case 1:
+		m.uninstallLogDir = (m.uninstallLogDir + 1) % 3
+	}
+	m.message = ""
+	return m, nil
+}
+
+func (m Model) handleUninstall() (Model, tea.Cmd) {
+	if m.language == nil {
+		m.message = "❌ No supported language detected"
+		return m, nil
+	}
+
+	m.message = fmt.Sprintf("Remove the generated logger package (call sites: %s, log directory: %s)? Press 'y' to confirm, any other key to cancel",
+		m.uninstallCallSitesLabel(), m.uninstallLogDir)
+	m.confirmingUninstall = true
+	return m, nil
+}
+
+func (m Model) confirmUninstall() (Model, tea.Cmd) {
+	m.confirmingUninstall = false
+
+	var notes []string
+	var remaining []string
+	if m.uninstallRewrite {
+		rewritten, skipped, err := m.language.RewriteCallSites(m.projectPath)
+		if err != nil {
+			m.message = fmt.Sprintf("❌ Failed to rewrite call sites: %v", err)
+			return m, nil
+		}
+		if len(rewritten) > 0 {
+			notes = append(notes, fmt.Sprintf("rewrote %d files to log/slog", len(rewritten)))
+		}
+		remaining = skipped
+	} else {
+		sites, err := m.language.CallSites(m.projectPath)
+		if err != nil {
+			m.message = fmt.Sprintf("❌ Failed to scan call sites: %v", err)
+			return m, nil
+		}
+		remaining = sites
+	}
+
+	if err := m.language.Uninstall(m.projectPath); err != nil {
+		m.message = fmt.Sprintf("❌ Error: %v", err)
+		return m, nil
+	}
+
+	switch m.uninstallLogDir {
+	case logDirArchive, logDirDelete:
+		logDir, err := detector.ResolveLogDir(m.projectPath, m.config)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("❌ %v", err))
+			break
+		}
+		if m.uninstallLogDir == logDirArchive {
+			archive, err := detector.ArchiveLogDir(logDir)
+			if err != nil {
+				notes = append(notes, fmt.Sprintf("❌ %v", err))
+			} else {
+				notes = append(notes, fmt.Sprintf("archived logs to %s", archive))
+			}
+		} else {
+			if err := detector.RemoveLogDir(logDir); err != nil {
+				notes = append(notes, fmt.Sprintf("❌ %v", err))
+			} else {
+				notes = append(notes, "deleted log directory")
+			}
+		}
+	}
+
+	m.message = "✅ Logger uninstalled"
+	if len(notes) > 0 {
+		m.message += ": " + strings.Join(notes, ", ")
+	}
+	if len(remaining) > 0 {
+		m.message += fmt.Sprintf("\n⚠️  %d files still import the logger package: %s", len(remaining), strings.Join(remaining, ", "))
+	}
+
+	m.logFiles = m.language.GetLogPaths(m.projectPath)
+	m.globalProjects = scanGlobalProjects()
+	m.screen = screenMain
+	m.cursor = 0
+	return m, tea.ClearScreen
+}
+
+func (m Model) uninstallCallSitesLabel() string {
+	if m.uninstallRewrite {
+		return "rewrite to log/slog"
+	}
+	return "leave in place"
+}
+
+func (m Model) renderUninstall() string {
+	if m.language == nil {
+		return "No supported language detected. Press ESC to go back."
+	}
+
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme().Accent).
+		Render(fmt.Sprintf("🧹 Uninstall logger from %s project", m.language.Name()))
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(m.theme().SelectedBg).
+		Foreground(m.theme().SelectedFg)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(m.theme().Text)
+
+	options := []string{
+		fmt.Sprintf("%-15s %s", "Call sites:", m.uninstallCallSitesLabel()),
+		fmt.Sprintf("%-15s %s", "Log directory:", m.uninstallLogDir),
+	}
+
+	var rows []string
+	for i, option := range options {
+		if i == m.cursor {
+			rows = append(rows, selectedStyle.Render("> "+option))
+		} else {
+			rows = append(rows, normalStyle.Render("  "+option))
+		}
+	}
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme().Muted).
+		Render("\nThis removes the generated logger package from the project.\nPress SPACE to change an option, ENTER to uninstall, ESC to cancel")
+
+	messageStr := ""
+	if m.message != "" {
+		messageStr = "\n\n" + lipgloss.NewStyle().
+			Foreground(m.theme().Message).
+			Render(m.message)
+	}
+
+	return fmt.Sprintf("%s\n\n%s%s%s", header, strings.Join(rows, "\n"), instructions, messageStr)
+}
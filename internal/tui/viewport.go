@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// viewport is a line-based scrolling window over a list of items, each of
+// which may wrap onto several display lines.
+type viewport struct {
+	items  []string
+	width  int
+	height int
+	offset int
+	// lines are the wrapped display lines; lineItem maps each back to the
+	// index of the item it came from.
+	lines    []string
+	lineItem []int
+}
+
+// defaultViewportHeight and defaultViewportWidth stand in for the size
+// until the first WindowSizeMsg has set it.
+const (
+	defaultViewportHeight = 20
+	defaultViewportWidth  = 80
+)
+
+// SetItems replaces the content, keeping the scroll position where possible.
+func (v *viewport) SetItems(items []string) {
+	v.items = items
+	v.layout()
+}
+
+// SetSize changes the visible area, re-wrapping the content and keeping the
+// first visible item in view.
+func (v *viewport) SetSize(width, height int) {
+	first := v.FirstItem()
+	v.width = width
+	v.height = height
+	v.layout()
+	v.ScrollToItem(first)
+}
+
+func (v *viewport) layout() {
+	v.lines = v.lines[:0]
+	v.lineItem = v.lineItem[:0]
+
+	style := lipgloss.NewStyle()
+	if v.width > 0 {
+		style = style.Width(v.width)
+	}
+	for i, item := range v.items {
+		rendered := item
+		if v.width > 0 && lipgloss.Width(item) > v.width {
+			rendered = style.Render(item)
+		}
+		for _, line := range strings.Split(rendered, "\n") {
+			v.lines = append(v.lines, line)
+			v.lineItem = append(v.lineItem, i)
+		}
+	}
+	v.clamp()
+}
+
+func (v *viewport) visibleHeight() int {
+	if v.height <= 0 {
+		return defaultViewportHeight
+	}
+	return v.height
+}
+
+func (v *viewport) visibleWidth() int {
+	if v.width <= 0 {
+		return defaultViewportWidth
+	}
+	return v.width
+}
+
+func (v *viewport) maxOffset() int {
+	return max(0, len(v.lines)-v.visibleHeight())
+}
+
+func (v *viewport) clamp() {
+	v.offset = max(0, min(v.offset, v.maxOffset()))
+}
+
+func (v *viewport) ScrollDown(n int) {
+	v.offset += n
+	v.clamp()
+}
+
+func (v *viewport) ScrollUp(n int) {
+	v.offset -= n
+	v.clamp()
+}
+
+func (v *viewport) PageDown()     { v.ScrollDown(v.visibleHeight()) }
+func (v *viewport) PageUp()       { v.ScrollUp(v.visibleHeight()) }
+func (v *viewport) HalfPageDown() { v.ScrollDown(max(1, v.visibleHeight()/2)) }
+func (v *viewport) HalfPageUp()   { v.ScrollUp(max(1, v.visibleHeight()/2)) }
+func (v *viewport) GotoTop()      { v.offset = 0 }
+func (v *viewport) GotoBottom()   { v.offset = v.maxOffset() }
+
+func (v *viewport) AtBottom() bool {
+	return v.offset >= v.maxOffset()
+}
+
+// FirstItem is the index of the item at the top of the window.
+func (v *viewport) FirstItem() int {
+	if v.offset < len(v.lineItem) {
+		return v.lineItem[v.offset]
+	}
+	return 0
+}
+
+// ScrollToItem puts the first line of item at the top of the window, or as
+// close as the content allows.
+func (v *viewport) ScrollToItem(item int) {
+	for i, owner := range v.lineItem {
+		if owner >= item {
+			v.offset = i
+			v.clamp()
+			return
+		}
+	}
+	v.GotoBottom()
+}
+
+// EnsureVisible scrolls the minimum amount needed to show all of item.
+func (v *viewport) EnsureVisible(item int) {
+	first, last := -1, -1
+	for i, owner := range v.lineItem {
+		if owner == item {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return
+	}
+	if first < v.offset {
+		v.offset = first
+	} else if last >= v.offset+v.visibleHeight() {
+		v.offset = last - v.visibleHeight() + 1
+	}
+	v.clamp()
+}
+
+// VisibleLines returns the display lines in the window and, for each, the
+// index of the item it belongs to.
+func (v *viewport) VisibleLines() ([]string, []int) {
+	end := min(len(v.lines), v.offset+v.visibleHeight())
+	return v.lines[v.offset:end], v.lineItem[v.offset:end]
+}
+
+func (v *viewport) View() string {
+	lines, _ := v.VisibleLines()
+	return strings.Join(lines, "\n")
+}
+
+// Position describes the window as first/last visible line (1-based), the
+// total line count and how far through the content it is.
+func (v *viewport) Position() (first, last, total, percent int) {
+	total = len(v.lines)
+	if total == 0 {
+		return 0, 0, 0, 100
+	}
+	first = v.offset + 1
+	last = min(total, v.offset+v.visibleHeight())
+	percent = last * 100 / total
+	return first, last, total, percent
+}
@@ -0,0 +1,228 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LFroesch/logdog/internal/detector"
+	"github.com/LFroesch/logdog/internal/logs"
+	"github.com/LFroesch/logdog/internal/query"
+	"github.com/LFroesch/logdog/internal/stats"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxChangedProjects caps how many projects the main screen summarizes, one
+// per digit key.
+const maxChangedProjects = 9
+
+// changesMsg carries what one project logged since the user's last visit.
+type changesMsg struct {
+	project string
+	changes stats.Changes
+}
+
+// scanChanges reads what every previously visited project logged since,
+// one project per command.
+func scanChanges(state detector.State) tea.Cmd {
+	root, err := detector.LogRoot()
+	if err != nil {
+		return nil
+	}
+	now := time.Now()
+	var cmds []tea.Cmd
+	for project, visit := range state.Projects {
+		if visit.LastViewed.IsZero() {
+			continue
+		}
+		project, since := project, visit.LastViewed
+		cmds = append(cmds, func() tea.Msg {
+			// A project that can't be read just shows no changes.
+			changes, _ := stats.ScanChanges(filepath.Join(root, project), since, now)
+			return changesMsg{project: project, changes: changes}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m Model) showChanges(msg changesMsg) (Model, tea.Cmd) {
+	if m.projectChanges == nil {
+		m.projectChanges = map[string]stats.Changes{}
+	}
+	m.projectChanges[msg.project] = msg.changes
+	return m, nil
+}
+
+// changedProjects are the projects with errors or warnings since the last
+// visit, most errors first.
+func (m Model) changedProjects() []string {
+	var projects []string
+	for project, changes := range m.projectChanges {
+		if !changes.Empty() {
+			projects = append(projects, project)
+		}
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		a, b := m.projectChanges[projects[i]], m.projectChanges[projects[j]]
+		if a.Errors != b.Errors {
+			return a.Errors > b.Errors
+		}
+		if a.Warnings != b.Warnings {
+			return a.Warnings > b.Warnings
+		}
+		return projects[i] < projects[j]
+	})
+	if len(projects) > maxChangedProjects {
+		projects = projects[:maxChangedProjects]
+	}
+	return projects
+}
+
+// rememberVisit records the projects being viewed as seen now, along with
+// the file and entry the viewer is on, so the next run can tell what is new
+// and reopen the file where it was left.
+func (m *Model) rememberVisit() {
+	projects := make(map[string]detector.ProjectState, len(m.state.Projects)+len(m.viewSources))
+	for name, visit := range m.state.Projects {
+		projects[name] = visit
+	}
+	now := time.Now()
+	for _, source := range m.viewSources {
+		visit := projects[source.Name]
+		visit.LastViewed = now
+		if m.viewingFile != "" && m.entryCursor < len(m.viewSource) {
+			visit.File = m.viewingFile
+			visit.Entry = m.viewSource[m.entryCursor]
+			visit.Line = m.allEntries[visit.Entry].Raw
+		}
+		projects[source.Name] = visit
+		delete(m.projectChanges, source.Name)
+	}
+	m.state.Projects = projects
+
+	if err := detector.SaveState(m.state); err != nil {
+		m.message = fmt.Sprintf("❌ Failed to save last visit: %v", err)
+	}
+}
+
+// restorePosition moves the cursor back to the entry the file was left on
+// last time, found by its line since the file may have grown or be
+// filtered differently. Of identical lines the one nearest where it was
+// wins, and if the entry is filtered out the next visible one is taken.
+func (m *Model) restorePosition(filePath string) {
+	visit := m.state.Projects[fileSource(filePath).Name]
+	if visit.File != filePath || visit.Line == "" {
+		return
+	}
+
+	source := -1
+	for i, entry := range m.allEntries {
+		if entry.Raw == visit.Line && (source < 0 || abs(i-visit.Entry) < abs(source-visit.Entry)) {
+			source = i
+		}
+	}
+	if source < 0 {
+		return
+	}
+
+	target := -1
+	for i, from := range m.viewSource {
+		if from >= source && (target < 0 || from < m.viewSource[target]) {
+			target = i
+		}
+	}
+	if target >= 0 {
+		m.moveEntryCursor(target - m.entryCursor)
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// openChanges shows the errors and warnings the i-th changed project logged
+// since the last visit. ESC from them goes to that project's log files.
+func (m Model) openChanges(i int) (Model, tea.Cmd) {
+	projects := m.changedProjects()
+	if i >= len(projects) {
+		return m, nil
+	}
+	project := projects[i]
+	changes := m.projectChanges[project]
+
+	root, err := detector.LogRoot()
+	if err != nil {
+		m.message = fmt.Sprintf("❌ %v", err)
+		return m, nil
+	}
+	q, err := query.Parse("level>=WARN since "+strconv.Quote(changes.Since.Format(time.DateTime)), time.Now())
+	if err != nil {
+		m.message = fmt.Sprintf("❌ Invalid query: %v", err)
+		return m, nil
+	}
+
+	m.selectedProject = project
+	m.logFiles = m.getLogFilesForProject(project)
+	m.cursor = 0
+	m.queryReturn = ""
+	m.message = "Running query..."
+	return m, runQuery(q, []logs.Source{{Name: project, Dir: filepath.Join(root, project)}})
+}
+
+// renderChanges summarizes what each visited project logged since, or is
+// empty when nothing did.
+func (m Model) renderChanges() string {
+	projects := m.changedProjects()
+	if len(projects) == 0 {
+		return ""
+	}
+
+	heading := lipgloss.NewStyle().Bold(true).Foreground(m.theme().Accent)
+	text := lipgloss.NewStyle().Foreground(m.theme().Text)
+	muted := lipgloss.NewStyle().Foreground(m.theme().Muted)
+
+	width := 0
+	for _, project := range projects {
+		width = max(width, lipgloss.Width(project))
+	}
+
+	lines := []string{heading.Render("🆕 Since your last visit")}
+	for i, project := range projects {
+		changes := m.projectChanges[project]
+		var counts []string
+		if changes.Errors > 0 {
+			counts = append(counts, lipgloss.NewStyle().Foreground(m.theme().levelColor("ERROR")).Bold(true).
+				Render(fmt.Sprintf("%d %s", changes.Errors, plural(changes.Errors, "error", "errors"))))
+		}
+		if changes.Warnings > 0 {
+			counts = append(counts, lipgloss.NewStyle().Foreground(m.theme().levelColor("WARN")).
+				Render(fmt.Sprintf("%d %s", changes.Warnings, plural(changes.Warnings, "warning", "warnings"))))
+		}
+		if n := len(changes.NewGroups); n > 0 {
+			counts = append(counts, text.Render(fmt.Sprintf("%d new %s", n, plural(n, "group", "groups"))))
+		}
+		lines = append(lines, fmt.Sprintf("%s %s  %s  %s",
+			muted.Render(fmt.Sprintf("%d", i+1)),
+			text.Render(cell(project, width)),
+			strings.Join(counts, muted.Render(", ")),
+			muted.Render("since "+formatSeen(changes.Since))))
+
+		if latest := changes.LatestError; latest != nil {
+			lines = append(lines, m.fitWidth(muted.Render(fmt.Sprintf("  %s  latest %s: %s",
+				strings.Repeat(" ", width), latest.Time.Format("Jan 02 15:04"), strings.SplitN(latest.Message, "\n", 2)[0]))))
+		}
+	}
+	keys := "1"
+	if len(projects) > 1 {
+		keys += "-" + strconv.Itoa(len(projects))
+	}
+	lines = append(lines, muted.Render("Press "+keys+" to see them"))
+	return strings.Join(lines, "\n")
+}